@@ -0,0 +1,66 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+type anchorKey struct{}
+
+// withAnchor 标记之后输出的文本所在块的锚点
+func withAnchor(ctx context.Context, blockId string) context.Context {
+	return context.WithValue(ctx, anchorKey{}, blockId)
+}
+
+func anchorOf(ctx context.Context) string {
+	blockId, _ := ctx.Value(anchorKey{}).(string)
+	return blockId
+}
+
+// anchorMarkdown 输出锚点，eg. <a id="doxcnXXX"></a>
+func anchorMarkdown(blockId string) string {
+	return fmt.Sprintf(`<a id="%s"></a>`, blockId)
+}
+
+// hasAnchor 块是否输出锚点：Anchors 为 heading 时只有标题，为 all 时所有包含文本的块
+func (p *DocxMarkdownProcessor) hasAnchor(block *larkdocx.Block) bool {
+	if block == nil || lo.FromPtr(block.BlockId) == "" {
+		return false
+	}
+	switch blockType := lo.FromPtr(block.BlockType); p.config().Anchors {
+	case AnchorHeading:
+		return (blockType == Page && !p.config().TitleFrontMatter) || (blockType >= Heading1 && blockType <= Heading9)
+	case AnchorAll:
+		switch blockType {
+		case Page:
+			return !p.config().TitleFrontMatter
+		case Text, Heading1, Heading2, Heading3, Heading4, Heading5, Heading6, Heading7, Heading8, Heading9,
+			Bullet, Ordered, Quote, Todo:
+			return true
+		}
+	}
+	return false
+}
+
+// internalLink 指向本文档中有锚点的块的链接改为锚点链接，eg. https://example.feishu.cn/docx/doxcnPage#doxcnBlock -> #doxcnBlock
+func (p *DocxMarkdownProcessor) internalLink(link string) string {
+	if p.config().Anchors == "" {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Fragment == "" || !p.hasAnchor(p.blocks[u.Fragment]) {
+		return link
+	}
+	// 链接指向其他文档时不改写
+	if u.Path != "" && !lo.ContainsBy([]string{p.DocumentId, p.Token}, func(token string) bool {
+		return token != "" && strings.HasSuffix(strings.TrimRight(u.Path, "/"), "/"+token)
+	}) {
+		return link
+	}
+	return "#" + u.Fragment
+}
@@ -0,0 +1,59 @@
+package lark_docx_md
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertJSON_Anchors(t *testing.T) {
+	data := []byte(`[
+		{"block_id": "doxcnAnchor", "block_type": 1, "children": ["h1", "text", "h7"], "page": {"elements": [{"text_run": {"content": "锚点"}}]}},
+		{"block_id": "h1", "block_type": 3, "parent_id": "doxcnAnchor", "heading1": {"elements": [{"text_run": {"content": "背景"}}]}},
+		{"block_id": "text", "block_type": 2, "parent_id": "doxcnAnchor", "text": {"elements": [
+			{"text_run": {"content": "见", "text_element_style": {"link": {"url": "https%3A%2F%2Fexample.feishu.cn%2Fdocx%2FdoxcnAnchor%23h7"}}}},
+			{"text_run": {"content": "正文", "text_element_style": {"link": {"url": "https%3A%2F%2Fexample.feishu.cn%2Fdocx%2FdoxcnAnchor%23text"}}}},
+			{"text_run": {"content": "其他", "text_element_style": {"link": {"url": "https%3A%2F%2Fexample.feishu.cn%2Fdocx%2FdoxcnOther%23h1"}}}}
+		]}},
+		{"block_id": "h7", "block_type": 9, "parent_id": "doxcnAnchor", "heading7": {"elements": [{"text_run": {"content": "细节"}}]}}
+	]`)
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			"heading",
+			[]Option{UseAnchors(AnchorHeading), UseDeepHeading(DeepHeadingHTML), UseTOC(0, 0, "")},
+			"# <a id=\"doxcnAnchor\"></a>锚点\n\n" +
+				"- [背景](#h1)\n  - [细节](#h7)\n\n" +
+				"# <a id=\"h1\"></a>背景\n\n" +
+				"[见](#h7)[正文](https://example.feishu.cn/docx/doxcnAnchor#text)[其他](https://example.feishu.cn/docx/doxcnOther#h1)\n\n" +
+				"<h6 data-level=\"7\" id=\"h7\">细节</h6>",
+		},
+		{
+			"all",
+			[]Option{UseAnchors(AnchorAll), UseTitleFrontMatter()},
+			"---\ntitle: \"锚点\"\n---\n\n" +
+				"# <a id=\"h1\"></a>背景\n\n" +
+				"<a id=\"text\"></a>[见](#h7)[正文](#text)[其他](https://example.feishu.cn/docx/doxcnOther#h1)\n\n" +
+				"###### <a id=\"h7\"></a>细节",
+		},
+		{
+			"none",
+			nil,
+			"# 锚点\n\n" +
+				"# 背景\n\n" +
+				"[见](https://example.feishu.cn/docx/doxcnAnchor#h7)[正文](https://example.feishu.cn/docx/doxcnAnchor#text)[其他](https://example.feishu.cn/docx/doxcnOther#h1)\n\n" +
+				"###### 细节",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertJSON(data, tt.opts...)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(got, tt.want+"\n\n***"), got)
+		})
+	}
+}
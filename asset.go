@@ -0,0 +1,224 @@
+package lark_docx_md
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// assetManifestName 静态文件清单的默认文件名，保存在静态文件目录下
+const assetManifestName = ".lark_docx_md.json"
+
+// AssetStore 静态文件仓库，负责静态文件的命名、去重以及清理不再被引用的文件，文件内容保存在 Store 中
+// 多个文档共用同一个 AssetStore 时，相同的文件只会下载和保存一次
+type AssetStore struct {
+	Store        StaticStore // 静态文件存储
+	ManifestPath string      // 静态文件清单路径，为空时清单只保存在内存中
+	ContentHash  bool        // 使用文件内容的 sha256 命名文件，内容相同的文件只保存一份
+
+	mu       sync.Mutex
+	manifest *assetManifest
+}
+
+type assetManifest struct {
+	Assets     map[string]*assetEntry `json:"assets"`     // file token -> 文件信息
+	References map[string][]string    `json:"references"` // document id -> 文档引用的 file token
+}
+
+type assetEntry struct {
+	Name   string `json:"name"`   // 文件名
+	URL    string `json:"url"`    // 文件在 Markdown 中的地址
+	Size   int64  `json:"size"`   // 文件大小
+	Sha256 string `json:"sha256"` // 文件内容的 sha256
+}
+
+func NewAssetStore(store StaticStore, manifestPath string, contentHash bool) *AssetStore {
+	return &AssetStore{
+		Store:        store,
+		ManifestPath: manifestPath,
+		ContentHash:  contentHash,
+	}
+}
+
+// NewLocalAssetStore 创建保存在本地目录的静态文件仓库，清单保存在同一目录下
+func NewLocalAssetStore(dir, urlPrefix string, contentHash bool) *AssetStore {
+	return NewAssetStore(NewLocalStaticStore(dir, urlPrefix), filepath.Join(dir, assetManifestName), contentHash)
+}
+
+// Lookup 查找已保存的文件，返回文件地址，无需重新下载
+// 存储支持读取时会校验文件大小和哈希，与记录不一致时视为不存在
+func (s *AssetStore) Lookup(ctx context.Context, token string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.load().Assets[token]
+	if !ok || !s.verify(ctx, entry) {
+		return "", false
+	}
+	return entry.URL, true
+}
+
+// Save 保存 token 对应的文件并返回文件地址
+// 默认使用 name 作为文件名；开启 ContentHash 时使用内容哈希加上 name 的扩展名作为文件名
+func (s *AssetStore) Save(ctx context.Context, token, name string, r io.Reader) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 先写入临时文件计算哈希，不在内存中保存整个文件
+	tmp, err := os.CreateTemp("", "lark_docx_md-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	size, sum, err := hashReader(io.TeeReader(r, tmp))
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if s.ContentHash {
+		name = sum + filepath.Ext(name)
+	}
+
+	m := s.load()
+	entry := &assetEntry{Name: name, Size: size, Sha256: sum}
+	if same := m.find(name); same != nil && same.Sha256 == sum && s.verify(ctx, same) {
+		entry.URL = same.URL
+	} else if entry.URL, err = s.Store.Put(ctx, name, tmp); err != nil {
+		return "", err
+	}
+	m.Assets[token] = entry
+	return entry.URL, nil
+}
+
+// Reference 记录文档引用的全部文件并持久化清单，覆盖该文档之前的引用记录
+func (s *AssetStore) Reference(documentId string, tokens []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.load()
+	if len(tokens) == 0 {
+		delete(m.References, documentId)
+	} else {
+		tokens = append([]string(nil), tokens...)
+		sort.Strings(tokens)
+		m.References[documentId] = tokens
+	}
+	return s.flush()
+}
+
+// GC 清理不再被任何已导出文档引用的文件，返回被删除的文件名
+// 只会删除由 AssetStore 保存过的文件；存储不支持删除时只从清单中移除
+func (s *AssetStore) GC(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.load()
+	referenced := make(map[string]bool)
+	for _, tokens := range m.References {
+		for _, token := range tokens {
+			referenced[token] = true
+		}
+	}
+	orphans := make(map[string]bool)
+	for token, entry := range m.Assets {
+		if !referenced[token] {
+			delete(m.Assets, token)
+			orphans[entry.Name] = true
+		}
+	}
+
+	var names []string
+	for name := range orphans {
+		// 开启 ContentHash 时，同一个文件可能仍被其他 token 使用
+		if m.find(name) != nil {
+			continue
+		}
+		if remover, ok := s.Store.(StaticRemover); ok {
+			if err := remover.Remove(ctx, name); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, s.flush()
+}
+
+// verify 校验存储中的文件与记录是否一致，存储不支持读取时直接信任记录
+func (s *AssetStore) verify(ctx context.Context, entry *assetEntry) bool {
+	opener, ok := s.Store.(StaticOpener)
+	if !ok {
+		return true
+	}
+	rc, err := opener.Open(ctx, entry.Name)
+	if err != nil {
+		return false
+	}
+	defer rc.Close()
+	size, sum, err := hashReader(rc)
+	return err == nil && size == entry.Size && sum == entry.Sha256
+}
+
+// load 读取静态文件清单，清单不存在或无法解析时从空清单开始
+func (s *AssetStore) load() *assetManifest {
+	if s.manifest != nil {
+		return s.manifest
+	}
+
+	s.manifest = &assetManifest{}
+	if s.ManifestPath != "" {
+		if data, err := os.ReadFile(s.ManifestPath); err == nil {
+			_ = json.Unmarshal(data, s.manifest)
+		}
+	}
+	if s.manifest.Assets == nil {
+		s.manifest.Assets = make(map[string]*assetEntry)
+	}
+	if s.manifest.References == nil {
+		s.manifest.References = make(map[string][]string)
+	}
+	return s.manifest
+}
+
+func (s *AssetStore) flush() error {
+	if s.ManifestPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.load(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.ManifestPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.ManifestPath, data, 0o666)
+}
+
+// find 查找使用该文件名的文件
+func (m *assetManifest) find(name string) *assetEntry {
+	for _, entry := range m.Assets {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+func hashReader(r io.Reader) (int64, string, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,124 @@
+package lark_docx_md
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetStore_Save(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentHash bool
+		want        []string
+	}{
+		{
+			"named by token",
+			false,
+			[]string{"static/token-1.jpg", "static/token-2.jpg"},
+		},
+		{
+			"named by content hash",
+			true,
+			[]string{
+				"static/b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9.jpg",
+				"static/b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9.jpg",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := NewLocalAssetStore(dir, "static", tt.contentHash)
+
+			var got []string
+			for _, token := range []string{"token-1", "token-2"} {
+				url, err := s.Save(context.Background(), token, token+".jpg", strings.NewReader("hello world"))
+				assert.NoError(t, err)
+				got = append(got, url)
+			}
+			assert.Equal(t, tt.want, got)
+
+			for _, url := range got {
+				data, err := os.ReadFile(filepath.Join(dir, filepath.Base(url)))
+				assert.NoError(t, err)
+				assert.Equal(t, "hello world", string(data))
+			}
+		})
+	}
+}
+
+func TestAssetStore_SaveStream(t *testing.T) {
+	// 下载的内容先写入临时文件，保存后删除
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s := NewAssetStore(NewS3StaticStore(server.URL, "", "bucket", "access-key", "secret-key"), "", true)
+	data := strings.Repeat("image", 1<<16)
+	url, err := s.Save(context.Background(), "token", "image.png", iotest.OneByteReader(strings.NewReader(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/bucket/"+sha256Hex([]byte(data))+".png", url)
+	assert.Equal(t, data, string(fake.objects["/bucket/"+sha256Hex([]byte(data))+".png"]))
+
+	entries, err := os.ReadDir(tmp)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAssetStore_Lookup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocalAssetStore(dir, "static", false)
+
+	_, ok := s.Lookup(ctx, "token")
+	assert.False(t, ok)
+
+	url, err := s.Save(ctx, "token", "token.jpg", strings.NewReader("image"))
+	assert.NoError(t, err)
+	got, ok := s.Lookup(ctx, "token")
+	assert.True(t, ok)
+	assert.Equal(t, url, got)
+
+	// 清单持久化后，新的仓库也能找到已下载的文件
+	assert.NoError(t, s.Reference("doc", []string{"token"}))
+	got, ok = NewLocalAssetStore(dir, "static", false).Lookup(ctx, "token")
+	assert.True(t, ok)
+	assert.Equal(t, url, got)
+
+	// 文件内容被修改后需要重新下载
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "token.jpg"), []byte("IMAGE"), 0o666))
+	_, ok = s.Lookup(ctx, "token")
+	assert.False(t, ok)
+}
+
+func TestAssetStore_GC(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStaticStore("static")
+	s := NewAssetStore(store, "", true)
+
+	for token, content := range map[string]string{"a": "a", "b": "b", "c": "c", "c-copy": "c"} {
+		_, err := s.Save(ctx, token, token+".jpg", strings.NewReader(content))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, len(store.Names()))
+
+	assert.NoError(t, s.Reference("doc1", []string{"a", "b"}))
+	assert.NoError(t, s.Reference("doc2", []string{"b", "c", "c-copy"}))
+	// 重新导出后 doc1 不再引用 a，doc2 不再引用 c-copy
+	assert.NoError(t, s.Reference("doc1", []string{"b"}))
+	assert.NoError(t, s.Reference("doc2", []string{"b", "c"}))
+
+	removed, err := s.GC(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{sha256Hex([]byte("a")) + ".jpg"}, removed)
+	assert.ElementsMatch(t, []string{sha256Hex([]byte("b")) + ".jpg", sha256Hex([]byte("c")) + ".jpg"}, store.Names())
+}
@@ -0,0 +1,103 @@
+package lark_docx_md
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+var diagramTypeMap = map[int]string{
+	1: "flowchart",
+	2: "UML",
+}
+
+// BlockBoardMarkdown 画板通过导出图片接口下载到静态文件仓库，以图片输出
+// 导出图片接口直接返回图片，没有临时下载地址，所以 StaticAsURL 时只输出注释
+func (p *DocxMarkdownProcessor) BlockBoardMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	if block.Board == nil || lo.FromPtr(block.Board.Token) == "" {
+		return "<!-- empty board -->"
+	}
+	token := *block.Board.Token
+	alt, url := p.boardURL(ctx, token)
+	if url == "" {
+		return fmt.Sprintf("<!-- board %s is exported only when static files are downloaded -->", token)
+	}
+	return p.imageMarkdown(ctx, alt, url, block.Board.Width, block.Board.Height, block.Board.Align)
+}
+
+// BlockDiagramMarkdown 流程图和 UML 在获取文档所有块接口中只有绘图类型，没有画板 token，无法通过导出图片接口导出
+// 输出提示转换为画板的注释
+func (p *DocxMarkdownProcessor) BlockDiagramMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	typ := "diagram"
+	if block.Diagram != nil {
+		typ = lo.ValueOr(diagramTypeMap, lo.FromPtr(block.Diagram.DiagramType), typ)
+	}
+	return fmt.Sprintf("<!-- %s can not be exported by open api, convert it to a board -->", typ)
+}
+
+// BlockAddOnsMarkdown 文本绘图输出为 mermaid 或 plantuml 代码块，不支持其他文档小组件
+func (p *DocxMarkdownProcessor) BlockAddOnsMarkdown(ctx context.Context, block *larkdocx.Block) []string {
+	if block.AddOns == nil || lo.FromPtr(block.AddOns.ComponentTypeId) != TextDrawingComponentTypeId {
+		return []string{fmt.Sprintf("<!-- not support block type %d -->", *block.BlockType)}
+	}
+
+	var record struct {
+		Data string `json:"data"` // 绘图源码
+	}
+	if err := json.Unmarshal([]byte(lo.FromPtr(block.AddOns.Record)), &record); err != nil || record.Data == "" {
+		return []string{"<!-- empty text drawing -->"}
+	}
+	language := "mermaid"
+	if strings.HasPrefix(strings.TrimSpace(record.Data), "@start") {
+		language = "plantuml"
+	}
+	code := strings.TrimRight(record.Data, "\n")
+	fence := codeFence(code)
+	var texts []string
+	texts = append(texts, fence+language)
+	texts = append(texts, strings.Split(code, "\n")...)
+	texts = append(texts, fence)
+	return FixTexts(texts)
+}
+
+// boardURL 下载画板图片，返回图片默认的替代文本和地址，不下载静态文件或失败时地址为空
+// 画板内容会变化，每次导出都重新下载
+func (p *DocxMarkdownProcessor) boardURL(ctx context.Context, token string) (string, string) {
+	name := token + ".png"
+	if p.staticOffline() {
+		return token, p.staticPlaceholder(token, name)
+	}
+	if p.StaticAsURL {
+		// 画板没有临时下载地址
+		return "", ""
+	}
+
+	resp, err := p.LarkClient.Get(ctx, fmt.Sprintf("/open-apis/board/v1/whiteboards/%s/download_as_image", token), nil, larkcore.AccessTokenTypeTenant)
+	if err != nil {
+		log.Printf("lark download board %s fail: %s", token, err)
+		return "", ""
+	}
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+		codeError := &larkcore.CodeError{}
+		_ = json.Unmarshal(resp.RawBody, codeError)
+		log.Printf("lark download board %s fail: code:%d, msg:%s, requestId:%s", token, codeError.Code, codeError.Msg, resp.RequestId())
+		return "", ""
+	}
+
+	mdname, err := p.assetStore().Save(ctx, token, name, bytes.NewReader(resp.RawBody))
+	if err != nil {
+		log.Printf("save board %s fail: %s", token, err)
+		return "", ""
+	}
+	p.assetTokens = append(p.assetTokens, token)
+	return path.Base(mdname), mdname
+}
@@ -0,0 +1,125 @@
+package lark_docx_md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockBoardMarkdown(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.AddBoard("board-token", []byte("png"))
+
+	board := func(token string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().
+			BlockType(Board).
+			Board(larkdocx.NewBoardBuilder().Token(token).Width(400).Height(300).Align(AlignMid).Build()).
+			Build()
+	}
+	staticDir := t.TempDir()
+	tests := []struct {
+		name   string
+		opts   []Option
+		client bool
+		block  *larkdocx.Block
+		want   string
+	}{
+		{
+			"download static",
+			[]Option{DownloadStatic(staticDir, "static")},
+			true,
+			board("board-token"),
+			"![board-token.png](static/board-token.png)",
+		},
+		{
+			"html size",
+			[]Option{DownloadStatic(staticDir, "static"), UseImageSize(ImageSizeHTML)},
+			true,
+			board("board-token"),
+			`<p align="center"><img src="static/board-token.png" alt="board-token.png" width="400" height="300"/></p>`,
+		},
+		{
+			"static as url",
+			nil,
+			true,
+			board("board-token"),
+			"<!-- board board-token is exported only when static files are downloaded -->",
+		},
+		{
+			"download fail",
+			[]Option{DownloadStatic(staticDir, "static")},
+			true,
+			board("board-not-exist"),
+			"<!-- board board-not-exist is exported only when static files are downloaded -->",
+		},
+		{
+			"offline",
+			[]Option{UseStaticPlaceholder("static/{name}")},
+			false,
+			board("board-token"),
+			"![board-token](static/board-token.png)",
+		},
+		{
+			"without board",
+			[]Option{DownloadStatic(staticDir, "static")},
+			true,
+			larkdocx.NewBlockBuilder().BlockType(Board).Build(),
+			"<!-- empty board -->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage", tt.opts...)
+			if tt.client {
+				p.LarkClient = server.Client()
+			}
+			assert.Equal(t, tt.want, p.BlockBoardMarkdown(context.Background(), tt.block))
+		})
+	}
+
+	data, err := os.ReadFile(filepath.Join(staticDir, "board-token.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+}
+
+func TestDocxMarkdownProcessor_BlockAddOnsMarkdown(t *testing.T) {
+	addOns := func(typ, record string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().
+			BlockType(AddOns).
+			AddOns(larkdocx.NewAddOnsBuilder().ComponentTypeId(typ).Record(record).Build()).
+			Build()
+	}
+	tests := []struct {
+		name  string
+		block *larkdocx.Block
+		want  []string
+	}{
+		{
+			"mermaid",
+			addOns(TextDrawingComponentTypeId, `{"data":"graph TD\n  A --> B\n","theme":"default","view":"chart"}`),
+			[]string{"```mermaid\n0x3f3f3f", "graph TD\n0x3f3f3f", "  A --> B\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"plantuml",
+			addOns(TextDrawingComponentTypeId, `{"data":"@startuml\nA -> B\n@enduml","view":"chart"}`),
+			[]string{"```plantuml\n0x3f3f3f", "@startuml\n0x3f3f3f", "A -> B\n0x3f3f3f", "@enduml\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"other add-ons",
+			addOns("blk_other", `{}`),
+			[]string{"<!-- not support block type 40 -->"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &DocxMarkdownProcessor{}
+			assert.Equal(t, tt.want, p.BlockAddOnsMarkdown(context.Background(), tt.block))
+		})
+	}
+}
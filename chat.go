@@ -0,0 +1,59 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"github.com/samber/lo"
+)
+
+// chatAppLink 打开群聊的 AppLink
+const chatAppLink = "https://applink.feishu.cn/client/chat/open?openChatId=%s"
+
+// BlockChatCardMarkdown 群名片输出为群聊链接，eg. [群聊: 项目群](https://applink.feishu.cn/client/chat/open?openChatId=oc_xxx)
+// 无法获取群名称时只输出群聊链接，没有群 id 时只输出文字
+func (p *DocxMarkdownProcessor) BlockChatCardMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	if block.ChatCard == nil {
+		return "<!-- empty chat card -->"
+	}
+	chatId := lo.FromPtr(block.ChatCard.ChatId)
+	if chatId == "" {
+		return "群聊"
+	}
+	label := "群聊"
+	if name := p.chatName(ctx, chatId); name != "" {
+		label = fmt.Sprintf("群聊: %s", name)
+	}
+	return fmt.Sprintf("[%s](%s)", escapeText(withEscape(ctx, escapeLinkText), label, false), fmt.Sprintf(chatAppLink, chatId))
+}
+
+// chatName 通过获取群信息接口获取群名称，同一个群只请求一次，失败时返回空
+func (p *DocxMarkdownProcessor) chatName(ctx context.Context, chatId string) string {
+	if p.LarkClient == nil || chatId == "" {
+		return ""
+	}
+	if name, ok := p.chatNames[chatId]; ok {
+		return name
+	}
+	if p.chatNames == nil {
+		p.chatNames = make(map[string]string)
+	}
+
+	req := larkim.NewGetChatReqBuilder().ChatId(chatId).Build()
+	resp, err := p.LarkClient.Im.Chat.Get(ctx, req)
+	switch {
+	case err != nil:
+		log.Printf("lark get chat %s fail: %s", chatId, err)
+	case !resp.Success():
+		log.Printf("lark get chat %s fail: code:%d, msg:%s, requestId:%s", chatId, resp.Code, resp.Msg, resp.RequestId())
+	default:
+		p.chatNames[chatId] = lo.FromPtr(resp.Data.Name)
+		return p.chatNames[chatId]
+	}
+	// 失败的群也缓存，避免重复请求
+	p.chatNames[chatId] = ""
+	return ""
+}
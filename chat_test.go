@@ -0,0 +1,63 @@
+package lark_docx_md
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockChatCardMarkdown(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.AddChat("oc_1", "项目 [周会]")
+
+	chatCard := func(chatId string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().
+			BlockType(ChatCard).
+			ChatCard(larkdocx.NewChatCardBuilder().ChatId(chatId).Align(AlignLeft).Build()).
+			Build()
+	}
+	p := NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnPage")
+	ctx := context.Background()
+	want := `[群聊: 项目 \[周会\]](https://applink.feishu.cn/client/chat/open?openChatId=oc_1)`
+	assert.Equal(t, want, p.BlockChatCardMarkdown(ctx, chatCard("oc_1")))
+	assert.Equal(t, want, p.BlockChatCardMarkdown(ctx, chatCard("oc_1")))
+	assert.Equal(t, "[群聊](https://applink.feishu.cn/client/chat/open?openChatId=oc_2)", p.BlockChatCardMarkdown(ctx, chatCard("oc_2")))
+	assert.Equal(t, "[群聊](https://applink.feishu.cn/client/chat/open?openChatId=oc_2)", p.BlockChatCardMarkdown(ctx, chatCard("oc_2")))
+
+	// 同一个群只请求一次
+	var chats int
+	for _, req := range server.Requests() {
+		if strings.HasPrefix(req, "GET /open-apis/im/v1/chats/") {
+			chats++
+		}
+	}
+	assert.Equal(t, 2, chats)
+
+	// 没有群名片或群 id 时不输出链接
+	assert.Equal(t, "<!-- empty chat card -->", p.BlockChatCardMarkdown(ctx, larkdocx.NewBlockBuilder().BlockType(ChatCard).Build()))
+	assert.Equal(t, "群聊", p.BlockChatCardMarkdown(ctx, chatCard("")))
+
+	offline := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage")
+	assert.Equal(t, "[群聊](https://applink.feishu.cn/client/chat/open?openChatId=oc_1)", offline.BlockChatCardMarkdown(ctx, chatCard("oc_1")))
+}
+
+func TestConvertJSON_OmitChatCard(t *testing.T) {
+	data := []byte(`[
+		{"block_id": "doxcnPage", "block_type": 1, "children": ["chat", "text"], "page": {"elements": [{"text_run": {"content": "标题"}}]}},
+		{"block_id": "chat", "block_type": 20, "parent_id": "doxcnPage", "chat_card": {"chat_id": "oc_1", "align": 1}},
+		{"block_id": "text", "block_type": 2, "parent_id": "doxcnPage", "text": {"elements": [{"text_run": {"content": "正文"}}]}}
+	]`)
+	md, err := ConvertJSON(data, OmitChatCard())
+	assert.NoError(t, err)
+	assert.NotContains(t, md, "群聊")
+	assert.Contains(t, md, "# 标题\n\n正文")
+
+	md, err = ConvertJSON(data)
+	assert.NoError(t, err)
+	assert.Contains(t, md, "# 标题\n\n[群聊](https://applink.feishu.cn/client/chat/open?openChatId=oc_1)\n\n正文")
+}
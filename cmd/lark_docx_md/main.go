@@ -0,0 +1,256 @@
+// Command lark_docx_md 将飞书云文档转为 Markdown
+//
+//	lark_docx_md export -type docx -token <token> [-static-dir static -file-prefix static] [-o doc.md]
+//	lark_docx_md convert [-placeholder "static/{name}"] [-o doc.md] blocks.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/A11Might/lark_docx_md"
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	"github.com/samber/lo"
+)
+
+const usage = `Usage:
+  lark_docx_md export [flags]         export a lark document through the open api
+  lark_docx_md convert [flags] [file] convert exported block json offline, read stdin when file is omitted
+
+Run "lark_docx_md <command> -h" for the flags of each command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "convert":
+		err = convert(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	appId := fs.String("app-id", os.Getenv("LARK_APP_ID"), "lark app id, default $LARK_APP_ID")
+	appSecret := fs.String("app-secret", os.Getenv("LARK_APP_SECRET"), "lark app secret, default $LARK_APP_SECRET")
+	typ := fs.String("type", lark_docx_md.Docx, "document type, docx or wiki")
+	token := fs.String("token", "", "document token")
+	staticDir := fs.String("static-dir", "", "download static files into this directory, use tmp urls when empty")
+	filePrefix := fs.String("file-prefix", "", "prefix of downloaded static files in markdown, default static-dir")
+	comments := fs.Bool("comments", false, "export comments as footnotes")
+	output := fs.String("o", "", "output markdown file, default stdout")
+	var render renderFlags
+	render.register(fs)
+	_ = fs.Parse(args)
+
+	if *appId == "" || *appSecret == "" || *token == "" {
+		return fmt.Errorf("export: -app-id, -app-secret and -token are required")
+	}
+	opts, err := render.options()
+	if err != nil {
+		return err
+	}
+	if *comments {
+		opts = append(opts, lark_docx_md.UseComments())
+	}
+	if *staticDir != "" {
+		opts = append(opts, lark_docx_md.DownloadStatic(*staticDir, lo.Ternary(*filePrefix == "", *staticDir, *filePrefix)))
+	}
+
+	p := lark_docx_md.NewDocxMarkdownProcessor(lark.NewClient(*appId, *appSecret), *typ, *token, opts...)
+	if *output == "" {
+		if err := p.WriteMarkdown(context.Background(), os.Stdout); err != nil {
+			return err
+		}
+		_, err = fmt.Println()
+		return err
+	}
+	// 大文档逐块写入文件，不在内存中保留整篇文档
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteMarkdown(context.Background(), f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func convert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	placeholder := fs.String("placeholder", "{token}", "placeholder url of static files, {token} and {name} are replaced")
+	output := fs.String("o", "", "output markdown file, default stdout")
+	var render renderFlags
+	render.register(fs)
+	_ = fs.Parse(args)
+
+	var (
+		data []byte
+		err  error
+	)
+	if fs.NArg() == 0 || fs.Arg(0) == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	opts, err := render.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, lark_docx_md.UseStaticPlaceholder(*placeholder))
+	md, err := lark_docx_md.ConvertJSON(data, opts...)
+	if err != nil {
+		return err
+	}
+	return write(*output, md)
+}
+
+// renderFlags 两个命令共用的渲染选项
+type renderFlags struct {
+	imageSize    string
+	imageCaption bool
+	ghCallout    bool
+	textColor    string
+	codeLanguage string
+	codeTitle    bool
+	iframeHTML   bool
+	folded       bool
+	headingShift int
+	frontMatter  bool
+	deepHeading  string
+	anchors      string
+	toc          bool
+	tocMin       int
+	tocMax       int
+	tocMarker    string
+	omitChatCard bool
+	reminderDate string
+	reminderTime string
+	reminderTZ   string
+	jiraURL      string
+	jiraUser     string
+	jiraToken    string
+	jiraLinkOnly bool
+}
+
+func (f *renderFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.imageSize, "image-size", "", "output image size and alignment, html or pandoc")
+	fs.BoolVar(&f.imageCaption, "image-caption", false, "use image caption as alt text")
+	fs.BoolVar(&f.ghCallout, "gh-callout", false, "render callout blocks as github alerts")
+	fs.StringVar(&f.codeLanguage, "code-language", "", "language of code blocks without one, eg. text")
+	fs.BoolVar(&f.codeTitle, "code-title", false, "render code block captions as title attributes")
+	fs.BoolVar(&f.iframeHTML, "iframe-html", false, "render embedded pages as iframe tags")
+	fs.BoolVar(&f.folded, "folded-details", false, "render folded headings and text as details tags")
+	fs.IntVar(&f.headingShift, "heading-offset", 0, "add this to heading levels, eg. 1 renders heading 1 as ##")
+	fs.BoolVar(&f.frontMatter, "title-front-matter", false, "render the document title as front matter")
+	fs.StringVar(&f.deepHeading, "deep-heading", "", "render headings beyond level 6 as bold or html, default level 6 headings")
+	fs.StringVar(&f.anchors, "anchors", "", "add block id anchors to heading or all blocks and rewrite links to them")
+	fs.BoolVar(&f.toc, "toc", false, "generate a table of contents after the title or at -toc-marker")
+	fs.IntVar(&f.tocMin, "toc-min", 0, "highest heading level in the table of contents, default 1")
+	fs.IntVar(&f.tocMax, "toc-max", 0, "lowest heading level in the table of contents, default 9")
+	fs.StringVar(&f.tocMarker, "toc-marker", "", "replace the text block with this content by the table of contents, eg. [TOC]")
+	fs.StringVar(&f.textColor, "text-color", "", "render text and background colors, html, highlight or bold")
+	fs.StringVar(&f.reminderDate, "reminder-date-layout", "", "go time layout of whole day reminders, default 2006-01-02")
+	fs.StringVar(&f.reminderTime, "reminder-time-layout", "", "go time layout of reminders, default RFC 3339")
+	fs.StringVar(&f.reminderTZ, "reminder-timezone", "", "timezone of reminders and task due times, eg. Asia/Shanghai, default local")
+	fs.BoolVar(&f.omitChatCard, "omit-chat-card", false, "omit group chat cards, eg. for public exports")
+	fs.StringVar(&f.jiraURL, "jira-url", os.Getenv("JIRA_URL"), "jira base url to fetch issue summary and status, default $JIRA_URL")
+	fs.StringVar(&f.jiraUser, "jira-user", os.Getenv("JIRA_USER"), "jira username for basic auth, bearer auth if empty, default $JIRA_USER")
+	fs.StringVar(&f.jiraToken, "jira-token", os.Getenv("JIRA_TOKEN"), "jira api token, default $JIRA_TOKEN")
+	fs.BoolVar(&f.jiraLinkOnly, "jira-link-only", false, "link jira issues to -jira-url without fetching them")
+}
+
+func (f *renderFlags) options() ([]lark_docx_md.Option, error) {
+	if f.jiraLinkOnly && f.jiraURL == "" {
+		return nil, fmt.Errorf("-jira-link-only requires -jira-url")
+	}
+	var opts []lark_docx_md.Option
+	if f.imageSize != "" {
+		opts = append(opts, lark_docx_md.UseImageSize(f.imageSize))
+	}
+	if f.imageCaption {
+		opts = append(opts, lark_docx_md.UseImageCaption())
+	}
+	if f.omitChatCard {
+		opts = append(opts, lark_docx_md.OmitChatCard())
+	}
+	if f.jiraURL != "" {
+		jira := lark_docx_md.NewJiraClient(f.jiraURL, f.jiraUser, f.jiraToken)
+		jira.LinkOnly = f.jiraLinkOnly
+		opts = append(opts, lark_docx_md.UseJira(jira))
+	}
+	if f.ghCallout {
+		opts = append(opts, lark_docx_md.UseGhCalloutStyle())
+	}
+	if f.codeLanguage != "" {
+		opts = append(opts, lark_docx_md.UseCodeLanguage(f.codeLanguage))
+	}
+	if f.codeTitle {
+		opts = append(opts, lark_docx_md.UseCodeTitle())
+	}
+	if f.iframeHTML {
+		opts = append(opts, lark_docx_md.UseIframeHTML())
+	}
+	if f.folded {
+		opts = append(opts, lark_docx_md.UseFoldedDetails())
+	}
+	if f.headingShift != 0 {
+		opts = append(opts, lark_docx_md.UseHeadingOffset(f.headingShift))
+	}
+	if f.frontMatter {
+		opts = append(opts, lark_docx_md.UseTitleFrontMatter())
+	}
+	if f.deepHeading != "" {
+		opts = append(opts, lark_docx_md.UseDeepHeading(f.deepHeading))
+	}
+	if f.anchors != "" {
+		opts = append(opts, lark_docx_md.UseAnchors(f.anchors))
+	}
+	if f.toc {
+		opts = append(opts, lark_docx_md.UseTOC(f.tocMin, f.tocMax, f.tocMarker))
+	}
+	if f.textColor != "" {
+		opts = append(opts, lark_docx_md.UseTextColor(f.textColor, nil))
+	}
+	if f.reminderDate != "" || f.reminderTime != "" || f.reminderTZ != "" {
+		var loc *time.Location
+		if f.reminderTZ != "" {
+			var err error
+			if loc, err = time.LoadLocation(f.reminderTZ); err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, lark_docx_md.UseReminderFormat(f.reminderDate, f.reminderTime, loc))
+	}
+	return opts, nil
+}
+
+func write(output, md string) error {
+	if output == "" {
+		_, err := fmt.Println(md)
+		return err
+	}
+	return os.WriteFile(output, []byte(md), 0o666)
+}
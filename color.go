@@ -0,0 +1,95 @@
+package lark_docx_md
+
+import (
+	"fmt"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// ColorPalette 文本颜色和背景色到输出颜色的映射，未映射的颜色不输出
+type ColorPalette struct {
+	TextColors       map[int]string // 文本颜色 -> CSS 颜色，eg. TextColorRed: "#D83931"
+	BackgroundColors map[int]string // 背景色 -> CSS 颜色，eg. LightRed: "#FBBFBC"
+}
+
+// DefaultColorPalette 返回飞书文档中使用的所有颜色
+func DefaultColorPalette() *ColorPalette {
+	return &ColorPalette{
+		TextColors: map[int]string{
+			TextColorRed:    "#D83931",
+			TextColorOrange: "#DE7802",
+			TextColorYellow: "#DC9B04",
+			TextColorGreen:  "#2EA121",
+			TextColorBlue:   "#245BDB",
+			TextColorPurple: "#6425D0",
+			TextColorGray:   "#646A73",
+		},
+		BackgroundColors: map[int]string{
+			LightRed:    "#FBBFBC",
+			LightOrange: "#FED4A4",
+			LightYellow: "#F8E6AB",
+			LightGreen:  "#D9F5D6",
+			LightBlue:   "#E1EAFF",
+			LightPurple: "#ECE2FE",
+			LightGray:   "#EFF0F1",
+			Red:         "#F76964",
+			Orange:      "#FFA53D",
+			Yellow:      "#FFE928",
+			Green:       "#62D256",
+			Blue:        "#4E83FD",
+			Purple:      "#935AF6",
+			Gray:        "#BBBFC4",
+			SilverGray:  "#F2F3F5",
+		},
+	}
+}
+
+// colors 返回文本在调色板中的文本颜色和背景色，未映射时为空
+func (p *DocxMarkdownProcessor) colors(style *larkdocx.TextElementStyle) (string, string) {
+	palette := p.config().ColorPalette
+	if p.config().ColorStyle == "" || style == nil {
+		return "", ""
+	}
+	if palette == nil {
+		palette = DefaultColorPalette()
+	}
+	return palette.TextColors[lo.FromPtr(style.TextColor)], palette.BackgroundColors[lo.FromPtr(style.BackgroundColor)]
+}
+
+// colorStyle 按照颜色输出方式调整文本样式：ColorStyleBold 时有映射颜色的文本加粗
+func (p *DocxMarkdownProcessor) colorStyle(style *larkdocx.TextElementStyle) *larkdocx.TextElementStyle {
+	if p.config().ColorStyle != ColorStyleBold {
+		return style
+	}
+	if color, background := p.colors(style); color == "" && background == "" {
+		return style
+	}
+	bold := *style
+	bold.Bold = lo.ToPtr(true)
+	return &bold
+}
+
+// colorMarkup 返回文本颜色和背景色的开始和结束标记，没有映射颜色时为空
+func (p *DocxMarkdownProcessor) colorMarkup(style *larkdocx.TextElementStyle) (string, string) {
+	color, background := p.colors(style)
+	if color == "" && background == "" {
+		return "", ""
+	}
+	switch p.config().ColorStyle {
+	case ColorStyleHTML:
+		var css []string
+		if color != "" {
+			css = append(css, "color:"+color)
+		}
+		if background != "" {
+			css = append(css, "background-color:"+background)
+		}
+		return fmt.Sprintf(`<span style="%s">`, strings.Join(css, ";")), "</span>"
+	case ColorStyleHighlight:
+		return "==", "=="
+	default:
+		return "", ""
+	}
+}
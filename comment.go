@@ -0,0 +1,175 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+	"github.com/samber/lo"
+)
+
+// listComments 读出文档的所有局部评论及其回复
+func (p *DocxMarkdownProcessor) listComments(ctx context.Context) error {
+	p.comments = make(map[string]*larkdrive.FileComment)
+	pageToken := ""
+	for {
+		builder := larkdrive.NewListFileCommentReqBuilder().
+			FileToken(p.DocumentId).
+			FileType(Docx).
+			IsWhole(false).
+			UserIdType("open_id").
+			PageSize(100)
+		if pageToken != "" {
+			builder.PageToken(pageToken)
+		}
+		resp, err := p.LarkClient.Drive.V1.FileComment.List(ctx, builder.Build())
+		if err != nil {
+			return err
+		}
+		if !resp.Success() {
+			return fmt.Errorf("lark list document %s comments fail: code:%d, msg:%s, requestId:%s", p.DocumentId, resp.Code, resp.Msg, resp.RequestId())
+		}
+
+		for _, comment := range resp.Data.Items {
+			if lo.FromPtr(comment.HasMore) {
+				// 评论中只返回部分回复，读出所有回复
+				replies, err := p.listCommentReplies(ctx, lo.FromPtr(comment.CommentId))
+				if err != nil {
+					return err
+				}
+				comment.ReplyList = &larkdrive.ReplyList{Replies: replies}
+			}
+			p.comments[lo.FromPtr(comment.CommentId)] = comment
+		}
+		if !lo.FromPtr(resp.Data.HasMore) || lo.FromPtr(resp.Data.PageToken) == "" {
+			return nil
+		}
+		pageToken = *resp.Data.PageToken
+	}
+}
+
+// listCommentReplies 读出评论的所有回复
+func (p *DocxMarkdownProcessor) listCommentReplies(ctx context.Context, commentId string) ([]*larkdrive.FileCommentReply, error) {
+	var (
+		replies   []*larkdrive.FileCommentReply
+		pageToken string
+	)
+	for {
+		builder := larkdrive.NewListFileCommentReplyReqBuilder().
+			FileToken(p.DocumentId).
+			CommentId(commentId).
+			FileType(Docx).
+			UserIdType("open_id").
+			PageSize(100)
+		if pageToken != "" {
+			builder.PageToken(pageToken)
+		}
+		resp, err := p.LarkClient.Drive.V1.FileCommentReply.List(ctx, builder.Build())
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success() {
+			return nil, fmt.Errorf("lark list comment %s replies fail: code:%d, msg:%s, requestId:%s", commentId, resp.Code, resp.Msg, resp.RequestId())
+		}
+
+		replies = append(replies, resp.Data.Items...)
+		if !lo.FromPtr(resp.Data.HasMore) || lo.FromPtr(resp.Data.PageToken) == "" {
+			return replies, nil
+		}
+		pageToken = *resp.Data.PageToken
+	}
+}
+
+// commentRefs 输出文本元素上评论的脚注引用，评论范围跨多个元素时引用放在最后一个元素之后
+// rest 为同一段文本中之后的元素；同一条评论只引用一次
+func (p *DocxMarkdownProcessor) commentRefs(style *larkdocx.TextElementStyle, rest []*larkdocx.TextElement) string {
+	if len(p.comments) == 0 || style == nil {
+		return ""
+	}
+	refs := new(strings.Builder)
+	for _, id := range style.CommentIds {
+		if p.comments[id] == nil || lo.Contains(p.footnotes, id) {
+			continue
+		}
+		if lo.ContainsBy(rest, func(e *larkdocx.TextElement) bool {
+			s := elementStyle(e)
+			return s != nil && lo.Contains(s.CommentIds, id)
+		}) {
+			continue
+		}
+		p.footnotes = append(p.footnotes, id)
+		refs.WriteString(fmt.Sprintf("[^%d]", len(p.footnotes)))
+	}
+	return refs.String()
+}
+
+// elementStyle 返回文本元素的样式
+func elementStyle(e *larkdocx.TextElement) *larkdocx.TextElementStyle {
+	switch {
+	case e.TextRun != nil:
+		return e.TextRun.TextElementStyle
+	case e.MentionUser != nil:
+		return e.MentionUser.TextElementStyle
+	case e.MentionDoc != nil:
+		return e.MentionDoc.TextElementStyle
+	case e.Reminder != nil:
+		return e.Reminder.TextElementStyle
+	case e.File != nil:
+		return e.File.TextElementStyle
+	case e.Equation != nil:
+		return e.Equation.TextElementStyle
+	default:
+		return nil
+	}
+}
+
+// footnotesMarkdown 输出引用过的评论，eg. [^1]: **张三**: 评论内容 (resolved)
+// 回复作为脚注中之后的段落
+func (p *DocxMarkdownProcessor) footnotesMarkdown(ctx context.Context) string {
+	var notes []string
+	for i, id := range p.footnotes {
+		comment := p.comments[id]
+		var paragraphs []string
+		for _, reply := range lo.FromPtr(comment.ReplyList).Replies {
+			paragraphs = append(paragraphs, fmt.Sprintf("**%s**: %s", escapeText(ctx, p.userName(ctx, lo.FromPtr(reply.UserId)), false), p.replyMarkdown(ctx, reply)))
+		}
+		if len(paragraphs) == 0 {
+			paragraphs = append(paragraphs, "")
+		}
+		if lo.FromPtr(comment.IsSolved) {
+			paragraphs[0] += " (resolved)"
+		}
+		notes = append(notes, fmt.Sprintf("[^%d]: %s", i+1, strings.Join(paragraphs, "\n\n    ")))
+	}
+	return strings.Join(notes, "\n\n")
+}
+
+// replyMarkdown 输出回复内容，换行转为脚注中的换行
+func (p *DocxMarkdownProcessor) replyMarkdown(ctx context.Context, reply *larkdrive.FileCommentReply) string {
+	if reply.Content == nil {
+		return ""
+	}
+	buf := new(strings.Builder)
+	for _, e := range reply.Content.Elements {
+		switch {
+		case e.TextRun != nil:
+			buf.WriteString(escapeText(ctx, lo.FromPtr(e.TextRun.Text), false))
+		case e.DocsLink != nil:
+			url := lo.FromPtr(e.DocsLink.Url)
+			buf.WriteString(fmt.Sprintf("[%s](%s)", escapeText(withEscape(ctx, escapeLinkText), url, false), escapeURL(url)))
+		case e.Person != nil:
+			buf.WriteString("@" + escapeText(ctx, p.userName(ctx, lo.FromPtr(e.Person.UserId)), false))
+		}
+	}
+	return strings.ReplaceAll(strings.TrimSpace(buf.String()), "\n", "<br>\n    ")
+}
+
+// userName 获取用户姓名，失败时返回 open id
+func (p *DocxMarkdownProcessor) userName(ctx context.Context, openId string) string {
+	if user := p.users.user(ctx, p.LarkClient, openId); user != nil && lo.FromPtr(user.Name) != "" {
+		return *user.Name
+	}
+	return openId
+}
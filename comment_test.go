@@ -0,0 +1,68 @@
+package lark_docx_md
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_DocxMarkdownComments(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.PageSize = 1
+	assert.NoError(t, server.AddDocumentJSON("doxcnComment", []byte(`[
+		{"block_id": "doxcnComment", "block_type": 1, "children": ["text", "text2"], "page": {"elements": [{"text_run": {"content": "评论"}}]}},
+		{"block_id": "text", "block_type": 2, "parent_id": "doxcnComment", "text": {"elements": [
+			{"text_run": {"content": "这是"}},
+			{"text_run": {"content": "需要", "text_element_style": {"bold": true, "comment_ids": ["c1"]}}},
+			{"text_run": {"content": "确认", "text_element_style": {"comment_ids": ["c1", "c3"]}}},
+			{"text_run": {"content": "的内容"}}
+		]}},
+		{"block_id": "text2", "block_type": 2, "parent_id": "doxcnComment", "text": {"elements": [
+			{"text_run": {"content": "第二段", "text_element_style": {"comment_ids": ["c2", "c1"]}}}
+		]}}
+	]`)))
+	reply := func(userId string, elements ...*larkdrive.ReplyElement) *larkdrive.FileCommentReply {
+		return larkdrive.NewFileCommentReplyBuilder().UserId(userId).
+			Content(larkdrive.NewReplyContentBuilder().Elements(elements).Build()).Build()
+	}
+	text := func(s string) *larkdrive.ReplyElement {
+		return larkdrive.NewReplyElementBuilder().Type("text_run").TextRun(larkdrive.NewTextRunBuilder().Text(s).Build()).Build()
+	}
+	server.AddComment("doxcnComment", larkdrive.NewFileCommentBuilder().CommentId("c1").IsSolved(true).
+		ReplyList(&larkdrive.ReplyList{Replies: []*larkdrive.FileCommentReply{
+			reply("ou_1", text("数据来源？")),
+			reply("ou_2", text("见"), larkdrive.NewReplyElementBuilder().Type("person").Person(larkdrive.NewPersonBuilder().UserId("ou_1").Build()).Build(), text("的文档\n*已更新*")),
+		}}).Build())
+	server.AddComment("doxcnComment", larkdrive.NewFileCommentBuilder().CommentId("c2").IsSolved(false).
+		ReplyList(&larkdrive.ReplyList{Replies: []*larkdrive.FileCommentReply{
+			reply("ou_1", larkdrive.NewReplyElementBuilder().Type("docs_link").DocsLink(larkdrive.NewDocsLinkBuilder().Url("https://example.feishu.cn/docx/doxcnA").Build()).Build()),
+		}}).Build())
+	server.AddUser("ou_1", "张三")
+
+	p := NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnComment", UseComments())
+	got, err := p.DocxMarkdown(context.Background())
+	assert.NoError(t, err)
+	want := "# 评论\n\n" +
+		"这是**需要**确认[^1]的内容\n\n" +
+		"第二段[^2]\n\n" +
+		"[^1]: **张三**: 数据来源？ (resolved)\n\n" +
+		"    **ou_2**: 见@张三的文档<br>\n    \\*已更新\\*\n\n" +
+		"[^2]: **张三**: [https://example.feishu.cn/docx/doxcnA](https://example.feishu.cn/docx/doxcnA)\n\n***"
+	assert.True(t, strings.HasPrefix(got, want), got)
+
+	// 评论中的回复不完整时读出所有回复，同一个用户只查询一次
+	requests := server.Requests()
+	assert.Contains(t, requests, "GET /open-apis/drive/v1/files/doxcnComment/comments/c1/replies")
+	assert.Equal(t, 1, lo.Count(requests, "GET /open-apis/contact/v3/users/ou_1"))
+
+	// 不获取评论时不输出脚注
+	got, err = NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnComment").DocxMarkdown(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "# 评论\n\n这是**需要**确认的内容\n\n第二段\n\n***"), got)
+}
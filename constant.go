@@ -1,28 +1,42 @@
 package lark_docx_md
 
 const (
-	Page           = 1
-	Text           = 2
-	Heading1       = 3
-	Heading2       = 4
-	Heading3       = 5
-	Heading4       = 6
-	Heading5       = 7
-	Heading6       = 8
-	Heading7       = 9
-	Heading8       = 10
-	Heading9       = 11
-	Bullet         = 12
-	Ordered        = 13
-	Code           = 14
-	Quote          = 15
-	Todo           = 17
-	Callout        = 19
-	Divider        = 22
-	Image          = 27
-	Table          = 31
-	TableCell      = 32
-	QuoteContainer = 34
+	Page            = 1
+	Text            = 2
+	Heading1        = 3
+	Heading2        = 4
+	Heading3        = 5
+	Heading4        = 6
+	Heading5        = 7
+	Heading6        = 8
+	Heading7        = 9
+	Heading8        = 10
+	Heading9        = 11
+	Bullet          = 12
+	Ordered         = 13
+	Code            = 14
+	Quote           = 15
+	Todo            = 17
+	Callout         = 19
+	ChatCard        = 20
+	Diagram         = 21
+	Divider         = 22
+	Iframe          = 26
+	Image           = 27
+	Table           = 31
+	TableCell       = 32
+	QuoteContainer  = 34
+	Task            = 35
+	Okr             = 36
+	OkrObjective    = 37
+	OkrKeyResult    = 38
+	OkrProgress     = 39
+	AddOns          = 40
+	JiraIssue       = 41
+	Board           = 43
+	LinkPreview     = 48
+	SourceSynced    = 49
+	ReferenceSynced = 50
 )
 
 const (
@@ -199,6 +213,58 @@ const (
 	SilverGray
 )
 
+// 内嵌网页的类型
+var iframeTypeMap = map[int]string{
+	1:  "Bilibili",
+	2:  "西瓜视频",
+	3:  "优酷",
+	4:  "Airtable",
+	5:  "百度地图",
+	6:  "高德地图",
+	8:  "Figma",
+	9:  "墨刀",
+	10: "Canva",
+	11: "CodePen",
+	12: "飞书问卷",
+	13: "金数据",
+	14: "Google Map",
+	15: "YouTube",
+}
+
+// 文档小组件类型
+const (
+	TextDrawingComponentTypeId = "blk_631fefbbae02400430b8f9f4" // 文本绘图，内容为 Mermaid 或 PlantUML
+)
+
+const (
+	TextColorRed = iota + 1
+	TextColorOrange
+	TextColorYellow
+	TextColorGreen
+	TextColorBlue
+	TextColorPurple
+	TextColorGray
+)
+
+// 文本颜色和背景色的输出方式
+const (
+	ColorStyleHTML      = "html"      // <span style="color:...;background-color:...">
+	ColorStyleHighlight = "highlight" // ==highlight==
+	ColorStyleBold      = "bold"      // 有颜色的文本加粗
+)
+
+// 输出锚点的块
+const (
+	AnchorHeading = "heading" // 文档标题和标题
+	AnchorAll     = "all"     // 所有包含文本的块
+)
+
+// 超过六级的标题的输出方式
+const (
+	DeepHeadingBold = "bold" // **标题**
+	DeepHeadingHTML = "html" // <h6 data-level="7">标题</h6>
+)
+
 var backgroundColorMap = map[int]string{
 	LightRed:    "[!CAUTION]",
 	LightOrange: "[!WARNING]",
@@ -1180,6 +1246,17 @@ const (
 	AlignRight
 )
 
+const (
+	ImageSizeHTML   = "html"   // <img src="url" width="100" height="100"/>
+	ImageSizePandoc = "pandoc" // ![alt](url){width=100px height=100px}
+)
+
+const (
+	SyncAdded   = "added"
+	SyncUpdated = "updated"
+	SyncDeleted = "deleted"
+)
+
 const (
 	Docx = "docx"
 	Wiki = "wiki"
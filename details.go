@@ -0,0 +1,44 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// foldedText 返回折叠了子块的标题或文本块的文本，没有折叠时返回空
+func foldedText(block *larkdocx.Block) *larkdocx.Text {
+	var text *larkdocx.Text
+	switch lo.FromPtr(block.BlockType) {
+	case Text:
+		text = block.Text
+	case Heading1, Heading2, Heading3, Heading4, Heading5, Heading6, Heading7, Heading8, Heading9:
+		text = headingText(block)
+	}
+	if text == nil || text.Style == nil || !lo.FromPtr(text.Style.Folded) {
+		return nil
+	}
+	return text
+}
+
+// isFoldedDetails 块是否按照 FoldedDetails 输出为 <details>，此时标题不是 Markdown 标题
+func (p *DocxMarkdownProcessor) isFoldedDetails(node *Node) bool {
+	return p.config().FoldedDetails && len(node.ChildrenNode) > 0 && foldedText(node.Block) != nil
+}
+
+// BlockFoldedMarkdown 折叠的标题和文本块输出为 <details>，块的纯文本作为 <summary>，子块不缩进
+// HTML 块中不解析 Markdown，所以 <summary> 不加样式，只转义 HTML
+func (p *DocxMarkdownProcessor) BlockFoldedMarkdown(ctx context.Context, text *larkdocx.Text, subBlockTexts []string) (texts []string) {
+	summary := html.EscapeString(strings.ReplaceAll(p.TextMarkdown(ctx, text, true), "\n", " "))
+	if blockId := anchorOf(ctx); blockId != "" {
+		summary = anchorMarkdown(blockId) + summary
+	}
+	texts = append(texts, FixTexts([]string{"<details>", fmt.Sprintf("<summary>%s</summary>", summary)})...)
+	texts = append(texts, subBlockTexts...)
+	texts = append(texts, "</details>")
+	return texts
+}
@@ -0,0 +1,22 @@
+package lark_docx_md
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertJSON_FoldedDetails(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", "details.json"))
+	assert.NoError(t, err)
+	got, err := ConvertJSON(data, UseFoldedDetails())
+	assert.NoError(t, err)
+	want := "# 折叠\n\n" +
+		"<details>\n<summary>折叠的标题 &lt;b&gt;</summary>\n\n标题下的内容\n\n</details>\n\n" +
+		"<details>\n<summary>折叠的文本</summary>\n\n- 文本下的列表\n\n</details>\n\n" +
+		"## 展开的标题\n\n展开的内容\n\n***"
+	assert.True(t, strings.HasPrefix(got, want), got)
+}
@@ -2,10 +2,15 @@ package lark_docx_md
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"log"
 	"strings"
+	"time"
 
 	lark "github.com/larksuite/oapi-sdk-go/v3"
 	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
 	larkwiki "github.com/larksuite/oapi-sdk-go/v3/service/wiki/v2"
 	"github.com/samber/lo"
 )
@@ -13,8 +18,41 @@ import (
 type Config struct {
 	StaticDir    string // 如果需要下载静态文件，那么需要指定静态文件的目录
 	FilePrefix   string // 针对静态文件，需要指定文件在 Markdown 中的前缀
-	StaticAsURL  bool   // 不下载静态文件，直接把静态文件的 URL 插入到 Markdown 中
+	StaticAsURL  bool   // 不下载静态文件，直接把静态文件的 URL 插入到 Markdown 中；画板没有临时下载地址，只输出注释
 	UseGhCallout bool   // 高亮块使用 github 样式
+	ImageSize    string // 图片宽高和对齐方式的输出格式，eg. html, pandoc；为空时不输出
+	ImageCaption bool   // 图片优先使用图片描述作为替代文本
+
+	CodeLanguage  string        // 代码块没有语言时使用的语言，eg. text
+	CodeTitle     bool          // 代码块标题输出为信息字符串中的 title 属性
+	IframeHTML    bool          // 内嵌网页输出为 <iframe>；默认输出为链接
+	LooseList     bool          // 列表项之间空一行；默认紧凑输出
+	FoldedDetails bool          // 折叠了子块的标题和文本块输出为 <details>；默认展开输出
+	ColorStyle    string        // 文本颜色和背景色的输出方式，eg. html, highlight, bold；为空时不输出
+	ColorPalette  *ColorPalette // 需要输出的颜色，为空时输出所有颜色
+
+	HeadingOffset    int    // 标题级别的偏移，eg. 为 1 时一级标题输出为 ##，文档标题仍为 #；为负数时标题最小为 #
+	TitleFrontMatter bool   // 文档标题输出为 front matter 中的 title，不输出为 # 标题
+	DeepHeading      string // 超过六级的标题的输出方式，eg. bold, html；为空时输出为六级标题
+	Anchors          string // 输出块 id 作为锚点的块，eg. heading, all；为空时不输出
+
+	TOC         bool   // 输出目录
+	TOCMinLevel int    // 目录包含的最高标题级别，为 0 时从一级标题开始
+	TOCMaxLevel int    // 目录包含的最低标题级别，为 0 时到九级标题为止
+	TOCMarker   string // 内容为 TOCMarker 的文本块替换为目录，eg. [TOC]；为空或找不到时目录插入在文档标题之后
+
+	Jira         *JiraClient // 获取 Jira issue 的标题和状态，为空时 Jira issue 只输出 key，只需要链接时设置 LinkOnly
+	OmitChatCard bool        // 不输出群名片，eg. 公开发布的文档
+	SyncedDepth  int         // 引用同步块的最大嵌套层数，为 0 时使用 DefaultSyncedDepth
+	Comments     bool        // 获取文档的局部评论，输出为脚注
+
+	ReminderDateLayout     string         // 整天的日期提醒的输出格式，为空时使用 DefaultReminderDateLayout
+	ReminderDateTimeLayout string         // 日期提醒的输出格式，为空时使用 DefaultReminderDateTimeLayout
+	ReminderLocation       *time.Location // 日期提醒和任务截止时间的时区，为空时使用本地时区
+
+	StaticPlaceholder string // 静态文件的占位地址，设置后不获取静态文件；{token} 替换为文件 token，{name} 替换为文件名
+
+	Assets *AssetStore // 静态文件仓库，负责下载文件的命名、去重、保存和清理
 }
 
 type Option func(*DocxMarkdownProcessor)
@@ -25,6 +63,24 @@ func DownloadStatic(staticDir, filePrefix string) Option {
 		p.StaticDir = staticDir
 		p.FilePrefix = filePrefix
 		p.StaticAsURL = false
+		p.Assets = NewLocalAssetStore(staticDir, filePrefix, false)
+	}
+}
+
+// DownloadStaticWithStore 下载图片等静态文件到指定的静态文件仓库，文件可以保存在本地、内存或对象存储中
+// 多个文档共用同一个仓库时可以对文件去重，并通过 AssetStore.GC 清理不再被引用的文件
+func DownloadStaticWithStore(store *AssetStore) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.StaticAsURL = false
+		p.Assets = store
+	}
+}
+
+// UseStaticPlaceholder 不获取图片等静态文件，使用占位地址代替，适用于离线转换
+// format 中的 {token} 替换为文件 token，{name} 替换为文件名，eg. "https://example.com/static/{name}"
+func UseStaticPlaceholder(format string) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.StaticPlaceholder = format
 	}
 }
 
@@ -35,12 +91,161 @@ func UseGhCalloutStyle() Option {
 	}
 }
 
+// UseImageSize 输出图片的宽高和对齐方式，format 可选 ImageSizeHTML、ImageSizePandoc
+func UseImageSize(format string) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.ImageSize = format
+	}
+}
+
+// UseImageCaption 图片优先使用图片描述作为替代文本，没有描述时使用图片 token
+func UseImageCaption() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.ImageCaption = true
+	}
+}
+
+// UseCodeLanguage 代码块没有语言或语言未知时使用 language
+func UseCodeLanguage(language string) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.CodeLanguage = language
+	}
+}
+
+// UseCodeTitle 代码块标题输出为信息字符串中的 title 属性，eg. ```go title="main.go"，适用于 Docusaurus、MkDocs
+// 标题同时包含单引号和双引号或包含反引号时无法放入信息字符串，不输出标题
+func UseCodeTitle() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.CodeTitle = true
+	}
+}
+
+// UseIframeHTML 内嵌网页输出为 <iframe> 标签，默认输出为链接
+func UseIframeHTML() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.IframeHTML = true
+	}
+}
+
+// UseJira 通过 Jira REST API 获取 Jira issue 的标题和状态
+func UseJira(client *JiraClient) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.Jira = client
+	}
+}
+
+// OmitChatCard 不输出群名片，默认输出为群聊链接
+func OmitChatCard() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.OmitChatCard = true
+	}
+}
+
+// UseSyncedDepth 设置引用同步块的最大嵌套层数，超过时不展开源同步块
+func UseSyncedDepth(depth int) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.SyncedDepth = depth
+	}
+}
+
+// UseReminderFormat 设置日期提醒的输出格式和时区，eg. UseReminderFormat("2006年1月2日", "2006年1月2日 15:04", loc)
+// 格式为空时使用 ISO 8601 格式，时区为空时使用本地时区，任务的截止时间也使用该时区
+func UseReminderFormat(dateLayout, dateTimeLayout string, loc *time.Location) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.ReminderDateLayout = dateLayout
+		p.ReminderDateTimeLayout = dateTimeLayout
+		p.ReminderLocation = loc
+	}
+}
+
+// UseComments 获取文档的局部评论及其回复，在评论的文本之后输出为脚注
+func UseComments() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.Comments = true
+	}
+}
+
+// UseFoldedDetails 折叠了子块的标题和文本块输出为 <details>，块的纯文本作为 <summary>，默认展开输出
+func UseFoldedDetails() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.FoldedDetails = true
+	}
+}
+
+// UseHeadingOffset 标题级别增加 offset，eg. 为 1 时文档标题为 #，一级标题为 ##，为 -1 时二级标题为 #，一级标题仍为 #
+func UseHeadingOffset(offset int) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.HeadingOffset = offset
+	}
+}
+
+// UseTitleFrontMatter 文档标题输出为 front matter 中的 title，eg. 使用静态网站生成器时
+func UseTitleFrontMatter() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.TitleFrontMatter = true
+	}
+}
+
+// UseDeepHeading 超过六级的标题输出为加粗段落或 <h6 data-level>，style 可选 DeepHeadingBold、DeepHeadingHTML
+// 默认输出为六级标题
+func UseDeepHeading(style string) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.DeepHeading = style
+	}
+}
+
+// UseAnchors 块的文本之前输出以块 id 为 id 的锚点，mode 可选 AnchorHeading、AnchorAll
+// 指向本文档中块的链接改为锚点链接，目录也使用这些锚点
+func UseAnchors(mode string) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.Anchors = mode
+	}
+}
+
+// UseTOC 输出标题级别在 [minLevel, maxLevel] 中的目录，为 0 时不限制
+// 目录替换内容为 marker 的文本块，marker 为空或找不到时插入在文档标题之后
+func UseTOC(minLevel, maxLevel int, marker string) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.TOC = true
+		p.TOCMinLevel = minLevel
+		p.TOCMaxLevel = maxLevel
+		p.TOCMarker = marker
+	}
+}
+
+// UseLooseList 列表项之间空一行，默认不空行
+func UseLooseList() Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.LooseList = true
+	}
+}
+
+// UseTextColor 输出文本颜色和背景色，style 可选 ColorStyleHTML、ColorStyleHighlight、ColorStyleBold
+// palette 指定需要输出的颜色及其 CSS 颜色，为空时使用 DefaultColorPalette
+func UseTextColor(style string, palette *ColorPalette) Option {
+	return func(p *DocxMarkdownProcessor) {
+		p.ColorStyle = style
+		p.ColorPalette = palette
+	}
+}
+
 type DocxMarkdownProcessor struct {
 	*Config
 	LarkClient *lark.Client // lark 客户端
 	DocumentId string       // docx 文档 token
 	Typ        string       // 文档类型，eg. docx, wiki
 	Token      string       // 文档 token
+
+	assetTokens []string                          // 本次导出引用的静态文件
+	extras      map[string]*blockExtra            // block id -> SDK 尚未定义的块属性
+	chatNames   map[string]string                 // chat id -> 群名称
+	blocks      map[string]*larkdocx.Block        // block id -> 块
+	comments    map[string]*larkdrive.FileComment // comment id -> 评论
+	footnotes   []string                          // 按引用顺序排列的评论 id
+	users       userCache                         // user open id -> 用户
+	toc         string                            // 目录，在目录的插入位置输出
+
+	syncedDocuments map[string]map[string]*larkdocx.Block // document id -> 源同步块所在文档的所有块
 }
 
 func NewDocxMarkdownProcessor(client *lark.Client, typ, token string, opts ...Option) *DocxMarkdownProcessor {
@@ -69,7 +274,118 @@ type ProcessItem struct {
 	QuoteContainer []*larkdocx.Block   // 引用容器
 }
 
+// DocxMarkdown 将文档转为 Markdown，基于 WriteMarkdown 实现
 func (p *DocxMarkdownProcessor) DocxMarkdown(ctx context.Context) (string, error) {
+	buf := new(strings.Builder)
+	if err := p.WriteMarkdown(ctx, buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// WriteMarkdown 将文档转为 Markdown 写入 w，每个顶层块转换后立即写入，不在内存中保留整篇文档
+func (p *DocxMarkdownProcessor) WriteMarkdown(ctx context.Context, w io.Writer) error {
+	if err := p.resolveDocumentId(ctx); err != nil {
+		return err
+	}
+
+	// 读出所有块
+	allBlock, err := p.listBlocks(ctx)
+	if err != nil {
+		return err
+	}
+	p.comments = nil
+	if p.Comments {
+		// 评论获取失败时不输出脚注
+		if err := p.listComments(ctx); err != nil {
+			log.Printf("list document %s comments fail: %s", p.DocumentId, err)
+		}
+	}
+
+	return p.WriteBlocks(ctx, w, allBlock)
+}
+
+// ConvertBlocks 将文档的所有块转为 Markdown，不需要 lark 客户端
+// blocks 为获取文档所有块接口返回的块列表，第一个块为文档的根块；没有客户端时图片等静态文件输出为占位符
+func ConvertBlocks(blocks []*larkdocx.Block, opts ...Option) (string, error) {
+	p := NewDocxMarkdownProcessor(nil, Docx, "", opts...)
+	return p.ConvertBlocks(context.Background(), blocks)
+}
+
+// ConvertBlocks 将文档的所有块转为 Markdown，第一个块为文档的根块
+func (p *DocxMarkdownProcessor) ConvertBlocks(ctx context.Context, allBlock []*larkdocx.Block) (string, error) {
+	buf := new(strings.Builder)
+	if err := p.WriteBlocks(ctx, buf, allBlock); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// WriteBlocks 将文档的所有块转为 Markdown 写入 w，第一个块为文档的根块
+func (p *DocxMarkdownProcessor) WriteBlocks(ctx context.Context, w io.Writer, allBlock []*larkdocx.Block) error {
+	if len(allBlock) == 0 {
+		return fmt.Errorf("lark document %s has no block", p.DocumentId)
+	}
+	if p.DocumentId == "" {
+		p.DocumentId = lo.FromPtr(allBlock[0].BlockId)
+	}
+
+	allBlockMap := lo.SliceToMap(allBlock, func(item *larkdocx.Block) (string, *larkdocx.Block) {
+		return *item.BlockId, item
+	})
+	p.blocks = allBlockMap
+	root := p.listTransformToTree(ctx, allBlock[0], allBlockMap)
+
+	// 转为 Markdown
+	p.assetTokens = nil
+	p.footnotes = nil
+	p.toc = ""
+	if p.config().TOC {
+		p.toc = p.tocMarkdown(ctx, p.headings(ctx, root))
+	}
+	mw := &markdownWriter{w: w}
+	p.writeRoot(ctx, mw, root)
+	if footnotes := p.footnotesMarkdown(ctx); footnotes != "" {
+		mw.write(footnotes)
+	}
+	if !p.StaticAsURL && !p.staticOffline() {
+		if err := p.assetStore().Reference(p.DocumentId, p.assetTokens); err != nil {
+			return err
+		}
+	}
+	// 广告位
+	mw.write("***\n_This MARKDOWN was generated with ❤️ by [lark_docx_md](https://github.com/A11Might/lark_docx_md)_")
+	return mw.close()
+}
+
+// writeRoot 输出根块，文档的顶层块逐个写入
+func (p *DocxMarkdownProcessor) writeRoot(ctx context.Context, mw *markdownWriter, root *Node) {
+	// 目录插入在文档标题之后
+	insertTOC := p.toc != "" && !p.containsTOCMarker(ctx, root)
+	if lo.FromPtr(root.BlockType) != Page {
+		texts := p.DocxBlockMarkdown(ctx, root)
+		if insertTOC && len(texts) > 0 {
+			texts = append(texts[:1], append([]string{p.toc}, texts[1:]...)...)
+		}
+		mw.write(texts...)
+		return
+	}
+
+	titleCtx := ctx
+	if p.hasAnchor(root.Block) {
+		titleCtx = withAnchor(ctx, *root.BlockId)
+	}
+	mw.write(p.BlockPageMarkdown(titleCtx, root.Block))
+	if insertTOC {
+		mw.write(p.toc)
+	}
+	p.childrenMarkdown(ctx, root, func(texts []string) {
+		mw.write(texts...)
+	})
+}
+
+// resolveDocumentId 根据文档类型获取 docx 文档 token
+func (p *DocxMarkdownProcessor) resolveDocumentId(ctx context.Context) error {
 	switch p.Typ {
 	case Docx:
 		p.DocumentId = p.Token
@@ -77,45 +393,84 @@ func (p *DocxMarkdownProcessor) DocxMarkdown(ctx context.Context) (string, error
 		req := larkwiki.NewGetNodeSpaceReqBuilder().Token(p.Token).Build()
 		resp, err := p.LarkClient.Wiki.V2.Space.GetNode(ctx, req)
 		if err != nil {
-			return "", err
+			return err
+		}
+		if !resp.Success() {
+			return fmt.Errorf("lark get wiki node %s fail: code:%d, msg:%s, requestId:%s", p.Token, resp.Code, resp.Msg, resp.RequestId())
 		}
 		p.DocumentId = *resp.Data.Node.ObjToken
 	default:
 	}
+	return nil
+}
 
-	req := larkdocx.NewListDocumentBlockReqBuilder().DocumentId(p.DocumentId).Build()
-	iterator, _ := p.LarkClient.Docx.V1.DocumentBlock.ListByIterator(ctx, req)
+// listBlocks 分页读出文档的所有块，同时从原始响应中解析 SDK 尚未定义的块属性
+func (p *DocxMarkdownProcessor) listBlocks(ctx context.Context) ([]*larkdocx.Block, error) {
+	p.extras = make(map[string]*blockExtra)
+	p.syncedDocuments = nil
+	return p.listDocumentBlocks(ctx, p.DocumentId)
+}
 
-	// 读出所有块
+// listDocumentBlocks 分页读出指定文档的所有块，块属性合并到 p.extras
+func (p *DocxMarkdownProcessor) listDocumentBlocks(ctx context.Context, documentId string) ([]*larkdocx.Block, error) {
 	var (
-		hasMore = true
-		block   *larkdocx.Block
-		err     error
-
-		allBlock []*larkdocx.Block
+		allBlock  []*larkdocx.Block
+		pageToken string
 	)
-	for ; hasMore && err == nil; hasMore, block, err = iterator.Next() {
-		allBlock = append(allBlock, block)
+	if p.extras == nil {
+		p.extras = make(map[string]*blockExtra)
 	}
-	if err != nil {
-		return "", err
+	for {
+		builder := larkdocx.NewListDocumentBlockReqBuilder().DocumentId(documentId).PageSize(500)
+		if pageToken != "" {
+			builder.PageToken(pageToken)
+		}
+		resp, err := p.LarkClient.Docx.V1.DocumentBlock.List(ctx, builder.Build())
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success() {
+			return nil, fmt.Errorf("lark list document %s blocks fail: code:%d, msg:%s, requestId:%s", documentId, resp.Code, resp.Msg, resp.RequestId())
+		}
+
+		allBlock = append(allBlock, resp.Data.Items...)
+		for id, extra := range parseBlockExtras(resp.RawBody) {
+			p.extras[id] = extra
+		}
+		if !lo.FromPtr(resp.Data.HasMore) || lo.FromPtr(resp.Data.PageToken) == "" {
+			return allBlock, nil
+		}
+		pageToken = *resp.Data.PageToken
 	}
-	if len(allBlock) > 1 {
-		allBlock = allBlock[1:] // 去除第一个空块
+}
+
+// extra 返回块的扩展属性，没有时返回空属性
+func (p *DocxMarkdownProcessor) extra(block *larkdocx.Block) *blockExtra {
+	if extra := p.extras[lo.FromPtr(block.BlockId)]; extra != nil {
+		return extra
 	}
+	return &blockExtra{}
+}
 
-	allBlockMap := lo.SliceToMap(allBlock, func(item *larkdocx.Block) (string, *larkdocx.Block) {
-		return *item.BlockId, item
-	})
-	root := p.listTransformToTree(ctx, allBlock[0], allBlockMap)
+// assetStore 返回静态文件仓库，未设置时使用 StaticDir 和 FilePrefix 创建
+func (p *DocxMarkdownProcessor) assetStore() *AssetStore {
+	if p.Assets == nil {
+		p.Assets = NewLocalAssetStore(p.StaticDir, p.FilePrefix, false)
+	}
+	return p.Assets
+}
 
-	// 转为 Markdown
-	var buf = new(strings.Builder)
-	buf.WriteString(strings.Join(p.DocxBlockMarkdown(ctx, root), "\n\n"))
-	// 广告位
-	buf.WriteString("\n\n***\n")
-	buf.WriteString("_This MARKDOWN was generated with ❤️ by [lark_docx_md](https://github.com/A11Might/lark_docx_md)_")
-	return strings.ReplaceAll(buf.String(), "0x3f3f3f\n\n", ""), nil
+// config 返回配置，未设置时返回默认配置
+func (p *DocxMarkdownProcessor) config() *Config {
+	if p.Config == nil {
+		return &Config{}
+	}
+	return p.Config
+}
+
+// staticOffline 是否不获取静态文件，只输出占位符
+func (p *DocxMarkdownProcessor) staticOffline() bool {
+	return p.LarkClient == nil || p.StaticPlaceholder != ""
 }
 
 type Node struct {
@@ -131,6 +486,13 @@ func (p *DocxMarkdownProcessor) listTransformToTree(ctx context.Context, rootLar
 	}
 
 	root := &Node{Block: rootLarkBlock}
+	if lo.FromPtr(root.BlockType) == ReferenceSynced {
+		root.ChildrenNode = p.syncedChildren(ctx, rootLarkBlock, larkBlockMap)
+		return root
+	}
+	if lo.FromPtr(root.BlockType) == SourceSynced {
+		ctx = withSyncedSource(ctx, lo.FromPtr(root.BlockId), false)
+	}
 
 	for _, c := range root.Children {
 		lb := larkBlockMap[c]
@@ -2,13 +2,17 @@ package lark_docx_md
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/A11Might/lark_docx_md/larktest"
 	lark "github.com/larksuite/oapi-sdk-go/v3"
 	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
 	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestListTransformToTree(t *testing.T) {
@@ -283,3 +287,95 @@ func TestListTransformToTree(t *testing.T) {
 		})
 	}
 }
+
+func TestDocxMarkdownProcessor_DocxMarkdown(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.PageSize = 2
+
+	textBlock := func(id string, typ int, content string) *larkdocx.Block {
+		text := larkdocx.NewTextBuilder().Elements(
+			[]*larkdocx.TextElement{
+				larkdocx.NewTextElementBuilder().TextRun(
+					larkdocx.NewTextRunBuilder().Content(
+						content,
+					).TextElementStyle(
+						larkdocx.NewTextElementStyleBuilder().
+							Bold(false).
+							InlineCode(false).
+							Italic(false).
+							Strikethrough(false).
+							Underline(false).
+							Build(),
+					).Build(),
+				).Build(),
+			},
+		).Style(
+			larkdocx.NewTextStyleBuilder().Align(AlignLeft).Build(),
+		).Build()
+		builder := larkdocx.NewBlockBuilder().BlockId(id).BlockType(typ)
+		switch typ {
+		case Page:
+			builder.Page(text)
+		case Heading1:
+			builder.Heading1(text)
+		default:
+			builder.Text(text)
+		}
+		return builder.Build()
+	}
+	page := textBlock("doxcnPage", Page, "文章标题")
+	page.Children = []string{"doxcnHeading", "doxcnText", "doxcnImage"}
+	server.AddDocument("doxcnPage",
+		page,
+		textBlock("doxcnHeading", Heading1, "一级标题"),
+		textBlock("doxcnText", Text, "文本"),
+		larkdocx.NewBlockBuilder().
+			BlockId("doxcnImage").
+			BlockType(Image).
+			Image(larkdocx.NewImageBuilder().Token("image-token").Build()).
+			Build(),
+	)
+	server.AddWikiNode("wikcnToken", "doxcnPage")
+	server.AddMedia("image-token", []byte("image"))
+
+	const footer = "\n\n***\n_This MARKDOWN was generated with ❤️ by [lark_docx_md](https://github.com/A11Might/lark_docx_md)_"
+	staticDir := t.TempDir()
+	tests := []struct {
+		name  string
+		typ   string
+		token string
+		opts  []Option
+		want  string
+	}{
+		{
+			"docx static as url",
+			Docx,
+			"doxcnPage",
+			nil,
+			"# 文章标题\n\n# 一级标题\n\n文本\n\n![image-token](" + server.URL + "/tmp/image-token)" + footer,
+		},
+		{
+			"wiki download static",
+			Wiki,
+			"wikcnToken",
+			[]Option{DownloadStatic(staticDir, "static")},
+			"# 文章标题\n\n# 一级标题\n\n文本\n\n![image-token.jpg](static/image-token.jpg)" + footer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(server.Client(), tt.typ, tt.token, tt.opts...)
+			got, err := p.DocxMarkdown(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	data, err := os.ReadFile(filepath.Join(staticDir, "image-token.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "image", string(data))
+
+	_, err = NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnNotExist").DocxMarkdown(context.Background())
+	assert.Error(t, err)
+}
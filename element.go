@@ -0,0 +1,57 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// 日期提醒默认输出为 ISO 8601 格式
+const (
+	DefaultReminderDateLayout     = "2006-01-02"
+	DefaultReminderDateTimeLayout = time.RFC3339
+)
+
+// reminderMarkdown 日期提醒输出为日期，整天的提醒只输出日期，eg. 2024-06-30, 2024-06-30T15:00:00+08:00
+func (p *DocxMarkdownProcessor) reminderMarkdown(ctx context.Context, reminder *larkdocx.Reminder) string {
+	ms, err := strconv.ParseInt(lo.FromPtr(reminder.ExpireTime), 10, 64)
+	if err != nil {
+		return ""
+	}
+	loc := p.reminderLocation()
+	layout := lo.Ternary(p.config().ReminderDateTimeLayout == "", DefaultReminderDateTimeLayout, p.config().ReminderDateTimeLayout)
+	if lo.FromPtr(reminder.IsWholeDay) {
+		layout = lo.Ternary(p.config().ReminderDateLayout == "", DefaultReminderDateLayout, p.config().ReminderDateLayout)
+	}
+	return escapeText(ctx, time.UnixMilli(ms).In(loc).Format(layout), false)
+}
+
+// reminderLocation 返回日期提醒和任务截止时间使用的时区，未配置时使用本地时区
+func (p *DocxMarkdownProcessor) reminderLocation() *time.Location {
+	if loc := p.config().ReminderLocation; loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// inlineFileMarkdown 内联附件按照静态文件配置输出为链接，文件名来自附件所在的文件块
+// 文件名只作为链接文字，保存时使用 token 加扩展名，避免同名附件互相覆盖
+func (p *DocxMarkdownProcessor) inlineFileMarkdown(ctx context.Context, file *larkdocx.InlineFile) string {
+	token := lo.FromPtr(file.FileToken)
+	name, filename := token, token
+	if block := p.blocks[lo.FromPtr(file.SourceBlockId)]; block != nil && block.File != nil && lo.FromPtr(block.File.Name) != "" {
+		name = *block.File.Name
+		filename = token + filepath.Ext(filepath.Base(name))
+	}
+	text := escapeText(withEscape(ctx, escapeLinkText), name, false)
+	_, url := p.staticURL(ctx, token, filename)
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, escapeURL(url))
+}
@@ -0,0 +1,102 @@
+package lark_docx_md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_TextMarkdownReminder(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*60*60)
+	text := func(wholeDay bool) *larkdocx.Text {
+		return larkdocx.NewTextBuilder().Elements([]*larkdocx.TextElement{
+			larkdocx.NewTextElementBuilder().TextRun(larkdocx.NewTextRunBuilder().Content("截止：").Build()).Build(),
+			larkdocx.NewTextElementBuilder().Reminder(larkdocx.NewReminderBuilder().
+				IsWholeDay(wholeDay).
+				ExpireTime("1719730800000"). // 2024-06-30 15:00:00 +08:00
+				TextElementStyle(larkdocx.NewTextElementStyleBuilder().Bold(true).Build()).
+				Build()).Build(),
+		}).Build()
+	}
+	tests := []struct {
+		name     string
+		opts     []Option
+		wholeDay bool
+		want     string
+	}{
+		{"iso date", []Option{UseReminderFormat("", "", shanghai)}, true, "截止：**2024-06-30**"},
+		{"iso date time", []Option{UseReminderFormat("", "", shanghai)}, false, "截止：**2024-06-30T15:00:00+08:00**"},
+		{"utc", []Option{UseReminderFormat("", "", time.UTC)}, false, "截止：**2024-06-30T07:00:00Z**"},
+		{"locale date", []Option{UseReminderFormat("2006年1月2日", "2006年1月2日 15:04", shanghai)}, true, "截止：**2024年6月30日**"},
+		{"locale date time", []Option{UseReminderFormat("Jan 2, 2006", "Jan 2, 2006 3:04 PM", shanghai)}, false, "截止：**Jun 30, 2024 3:00 PM**"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage", tt.opts...)
+			assert.Equal(t, tt.want, p.TextMarkdown(context.Background(), text(tt.wholeDay)))
+		})
+	}
+}
+
+func TestDocxMarkdownProcessor_TextMarkdownInlineFile(t *testing.T) {
+	text := larkdocx.NewTextBuilder().Elements([]*larkdocx.TextElement{
+		larkdocx.NewTextElementBuilder().TextRun(larkdocx.NewTextRunBuilder().Content("附件：").Build()).Build(),
+		larkdocx.NewTextElementBuilder().File(larkdocx.NewInlineFileBuilder().FileToken("boxcnFile").SourceBlockId("file").Build()).Build(),
+		larkdocx.NewTextElementBuilder().TextRun(larkdocx.NewTextRunBuilder().Content("，").Build()).Build(),
+		larkdocx.NewTextElementBuilder().File(larkdocx.NewInlineFileBuilder().FileToken("boxcnOther").Build()).Build(),
+	}).Build()
+
+	p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage", UseStaticPlaceholder("static/{name}"))
+	p.blocks = map[string]*larkdocx.Block{
+		"file": larkdocx.NewBlockBuilder().BlockId("file").BlockType(23).
+			File(larkdocx.NewFileBuilder().Token("boxcnFile").Name("会议纪要 (终版).pdf").Build()).Build(),
+	}
+	assert.Equal(t, "附件：[会议纪要 (终版).pdf](static/boxcnFile.pdf)，[boxcnOther](static/boxcnOther)", p.TextMarkdown(context.Background(), text))
+}
+
+func TestDocxMarkdownProcessor_TextMarkdownInlineFileDownload(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.AddMedia("boxcnFirst", []byte("first"))
+	server.AddMedia("boxcnSecond", []byte("second"))
+	server.AddMedia("boxcnEscape", []byte("escape"))
+
+	inline := func(token, block string) *larkdocx.TextElement {
+		return larkdocx.NewTextElementBuilder().File(larkdocx.NewInlineFileBuilder().FileToken(token).SourceBlockId(block).Build()).Build()
+	}
+	file := func(id, token, name string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().BlockId(id).BlockType(23).
+			File(larkdocx.NewFileBuilder().Token(token).Name(name).Build()).Build()
+	}
+	text := larkdocx.NewTextBuilder().Elements([]*larkdocx.TextElement{
+		inline("boxcnFirst", "first"),
+		inline("boxcnSecond", "second"),
+		inline("boxcnEscape", "escape"),
+	}).Build()
+
+	dir := filepath.Join(t.TempDir(), "static")
+	p := NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnPage", DownloadStatic(dir, "static"))
+	p.blocks = map[string]*larkdocx.Block{
+		"first":  file("first", "boxcnFirst", "report.pdf"),
+		"second": file("second", "boxcnSecond", "report.pdf"),
+		"escape": file("escape", "boxcnEscape", "../../escaped.txt"),
+	}
+	assert.Equal(t,
+		"[report.pdf](static/boxcnFirst.pdf)[report.pdf](static/boxcnSecond.pdf)[../../escaped.txt](static/boxcnEscape.txt)",
+		p.TextMarkdown(context.Background(), text),
+	)
+
+	for name, want := range map[string]string{"boxcnFirst.pdf": "first", "boxcnSecond.pdf": "second", "boxcnEscape.txt": "escape"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(filepath.Dir(dir)), "escaped.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
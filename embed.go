@@ -0,0 +1,33 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// BlockIframeMarkdown 内嵌网页输出为以网页类型命名的链接，IframeHTML 时输出为 <iframe>
+func (p *DocxMarkdownProcessor) BlockIframeMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	if block.Iframe == nil || block.Iframe.Component == nil || lo.FromPtr(block.Iframe.Component.Url) == "" {
+		return "<!-- empty iframe -->"
+	}
+	component := block.Iframe.Component
+	url := UnescapeUrl(*component.Url)
+	if p.config().IframeHTML {
+		return fmt.Sprintf(`<iframe src="%s" width="100%%" height="480" frameborder="0" allowfullscreen></iframe>`, html.EscapeString(url))
+	}
+	label := lo.ValueOr(iframeTypeMap, lo.FromPtr(component.IframeType), "Embed")
+	return fmt.Sprintf("[%s](%s)", escapeText(withEscape(ctx, escapeLinkText), label, false), escapeURL(url))
+}
+
+// BlockLinkPreviewMarkdown 链接预览输出为链接
+func (p *DocxMarkdownProcessor) BlockLinkPreviewMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	url := p.extra(block).linkPreviewURL()
+	if url == "" {
+		return fmt.Sprintf("<!-- not support block type %d -->", *block.BlockType)
+	}
+	return fmt.Sprintf("[%s](%s)", escapeText(withEscape(ctx, escapeLinkText), url, false), escapeURL(url))
+}
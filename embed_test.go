@@ -0,0 +1,75 @@
+package lark_docx_md
+
+import (
+	"context"
+	"testing"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockIframeMarkdown(t *testing.T) {
+	iframe := func(typ int, url string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().
+			BlockType(Iframe).
+			Iframe(larkdocx.NewIframeBuilder().Component(
+				larkdocx.NewIframeComponentBuilder().IframeType(typ).Url(url).Build(),
+			).Build()).
+			Build()
+	}
+	tests := []struct {
+		name   string
+		config *Config
+		block  *larkdocx.Block
+		want   string
+	}{
+		{
+			"youtube",
+			&Config{},
+			iframe(15, "https%3A%2F%2Fwww.youtube.com%2Fembed%2Fabc"),
+			"[YouTube](https://www.youtube.com/embed/abc)",
+		},
+		{
+			"figma",
+			nil,
+			iframe(8, "https%3A%2F%2Fwww.figma.com%2Ffile%2Fabc%2FDesign%3Fnode-id%3D1"),
+			"[Figma](https://www.figma.com/file/abc/Design?node-id=1)",
+		},
+		{
+			"other",
+			&Config{},
+			iframe(99, "https%3A%2F%2Fexample.com%2F"),
+			"[Embed](https://example.com/)",
+		},
+		{
+			"html",
+			&Config{IframeHTML: true},
+			iframe(1, "https%3A%2F%2Fplayer.bilibili.com%2Fplayer.html%3Fbvid%3DBV1%26page%3D1"),
+			`<iframe src="https://player.bilibili.com/player.html?bvid=BV1&amp;page=1" width="100%" height="480" frameborder="0" allowfullscreen></iframe>`,
+		},
+		{
+			"empty",
+			&Config{},
+			larkdocx.NewBlockBuilder().BlockType(Iframe).Iframe(larkdocx.NewIframeBuilder().Build()).Build(),
+			"<!-- empty iframe -->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &DocxMarkdownProcessor{Config: tt.config}
+			assert.Equal(t, tt.want, p.BlockIframeMarkdown(context.Background(), tt.block))
+		})
+	}
+}
+
+func TestDocxMarkdownProcessor_BlockLinkPreviewMarkdown(t *testing.T) {
+	block := larkdocx.NewBlockBuilder().BlockId("preview").BlockType(LinkPreview).Build()
+	p := &DocxMarkdownProcessor{}
+	assert.Equal(t, "<!-- not support block type 48 -->", p.BlockLinkPreviewMarkdown(context.Background(), block))
+
+	p.extras = map[string]*blockExtra{
+		"preview": {LinkPreview: &linkPreviewExtra{Url: lo.ToPtr("https%3A%2F%2Fgithub.com%2F")}},
+	}
+	assert.Equal(t, "[https://github.com/](https://github.com/)", p.BlockLinkPreviewMarkdown(context.Background(), block))
+}
@@ -0,0 +1,238 @@
+package lark_docx_md
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+)
+
+// emphasis 一种行内样式的开始和结束标记
+type emphasis struct {
+	open, close         string // Markdown 标记
+	htmlOpen, htmlClose string // Markdown 标记无法被识别为强调时使用的 HTML 标签，为空时标记总能被识别
+}
+
+var (
+	boldEmphasis      = emphasis{"**", "**", "<strong>", "</strong>"}
+	italicEmphasis    = emphasis{"*", "*", "<em>", "</em>"}
+	strikeEmphasis    = emphasis{"~~", "~~", "<del>", "</del>"}
+	underlineEmphasis = emphasis{"<u>", "</u>", "", ""}
+	highlightEmphasis = emphasis{"==", "==", "<mark>", "</mark>"}
+)
+
+// textSegment 样式相同的连续文本
+type textSegment struct {
+	style      *larkdocx.TextElementStyle
+	colorOpen  string
+	colorClose string
+	content    strings.Builder
+}
+
+func (s *textSegment) sameStyle(style *larkdocx.TextElementStyle, colorOpen string) bool {
+	return *s.style.Bold == *style.Bold &&
+		*s.style.InlineCode == *style.InlineCode &&
+		*s.style.Italic == *style.Italic &&
+		*s.style.Strikethrough == *style.Strikethrough &&
+		*s.style.Underline == *style.Underline &&
+		s.colorOpen == colorOpen
+}
+
+// emphases 返回文本的样式，从外到内排列，行内代码总在最内层，不在其中
+func (s *textSegment) emphases() []emphasis {
+	var emphases []emphasis
+	switch s.colorOpen {
+	case "":
+	case highlightEmphasis.open:
+		emphases = append(emphases, highlightEmphasis)
+	default:
+		emphases = append(emphases, emphasis{s.colorOpen, s.colorClose, "", ""})
+	}
+	if *s.style.Bold {
+		emphases = append(emphases, boldEmphasis)
+	}
+	if *s.style.Italic {
+		emphases = append(emphases, italicEmphasis)
+	}
+	if *s.style.Strikethrough {
+		emphases = append(emphases, strikeEmphasis)
+	}
+	if *s.style.Underline {
+		emphases = append(emphases, underlineEmphasis)
+	}
+	return emphases
+}
+
+// inlineToken 文本或样式的开始、结束标记
+type inlineToken struct {
+	text     string
+	emphasis *emphasis // 不为空时是样式标记
+	open     bool      // 是否为开始标记
+	pair     int       // 对应的开始或结束标记的下标
+	html     bool      // 是否使用 HTML 标签
+}
+
+// renderSegments 输出加上样式的文本
+// 样式按栈的方式开始和结束：相邻文本共有的样式保持不变，只结束和开始变化的样式
+// 样式标记移到首尾的空白之内；标记因为相邻字符无法被识别为强调时，改用 HTML 标签
+func renderSegments(ctx context.Context, segments []*textSegment) string {
+	var (
+		tokens  []*inlineToken
+		stack   []int // 未结束的样式的开始标记下标
+		pending string
+	)
+	closeTo := func(n int) {
+		for len(stack) > n {
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			tokens[open].pair = len(tokens)
+			tokens = append(tokens, &inlineToken{emphasis: tokens[open].emphasis, pair: open})
+		}
+	}
+	for k, segment := range segments {
+		var lead, core, trail string
+		if *segment.style.InlineCode {
+			core = codeSpan(ctx, segment.content.String())
+		} else {
+			lead, core, trail = splitSpace(segment.content.String())
+		}
+		if core == "" {
+			// 只有空白的文本不加样式
+			pending += lead
+			continue
+		}
+
+		want := segment.emphases()
+		// 持续时间更长的样式在外层，减少结束和重新开始
+		sort.SliceStable(want, func(i, j int) bool {
+			return emphasisSpan(segments[k:], want[i]) > emphasisSpan(segments[k:], want[j])
+		})
+		keep := 0
+		for keep < len(stack) && containsEmphasis(want, *tokens[stack[keep]].emphasis) {
+			keep++
+		}
+		closeTo(keep)
+		if pending+lead != "" {
+			tokens = append(tokens, &inlineToken{text: pending + lead})
+		}
+		for i := range want {
+			if !stackContains(tokens, stack, want[i]) {
+				stack = append(stack, len(tokens))
+				tokens = append(tokens, &inlineToken{emphasis: &want[i], open: true})
+			}
+		}
+		tokens = append(tokens, &inlineToken{text: core})
+		pending = trail
+	}
+	closeTo(0)
+	if pending != "" {
+		tokens = append(tokens, &inlineToken{text: pending})
+	}
+
+	for i, token := range tokens {
+		if token.emphasis != nil && token.open && token.emphasis.htmlOpen != "" {
+			html := !leftFlanking(prevRune(tokens, i), nextRune(tokens, i)) ||
+				!rightFlanking(prevRune(tokens, token.pair), nextRune(tokens, token.pair))
+			token.html, tokens[token.pair].html = html, html
+		}
+	}
+
+	buf := new(strings.Builder)
+	for _, token := range tokens {
+		switch {
+		case token.emphasis == nil:
+			buf.WriteString(token.text)
+		case token.open && token.html:
+			buf.WriteString(token.emphasis.htmlOpen)
+		case token.open:
+			buf.WriteString(token.emphasis.open)
+		case token.html:
+			buf.WriteString(token.emphasis.htmlClose)
+		default:
+			buf.WriteString(token.emphasis.close)
+		}
+	}
+	return buf.String()
+}
+
+// emphasisSpan 返回从第一段文本开始连续具有样式 e 的文本段数，只有空白的文本不计算
+func emphasisSpan(segments []*textSegment, e emphasis) int {
+	n := 0
+	for _, segment := range segments {
+		if !*segment.style.InlineCode && strings.TrimSpace(segment.content.String()) == "" {
+			continue
+		}
+		if !containsEmphasis(segment.emphases(), e) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func containsEmphasis(emphases []emphasis, e emphasis) bool {
+	for _, v := range emphases {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+func stackContains(tokens []*inlineToken, stack []int, e emphasis) bool {
+	for _, i := range stack {
+		if *tokens[i].emphasis == e {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSpace 拆分文本首尾的空白
+func splitSpace(s string) (string, string, string) {
+	core := strings.TrimLeftFunc(s, unicode.IsSpace)
+	lead := s[:len(s)-len(core)]
+	trimmed := strings.TrimRightFunc(core, unicode.IsSpace)
+	return lead, trimmed, core[len(trimmed):]
+}
+
+// prevRune 返回标记前的字符，样式标记都是标点，行首视为空白
+func prevRune(tokens []*inlineToken, i int) rune {
+	if i == 0 {
+		return ' '
+	}
+	if tokens[i-1].emphasis != nil {
+		return '*'
+	}
+	r, _ := utf8.DecodeLastRuneInString(tokens[i-1].text)
+	return r
+}
+
+// nextRune 返回标记后的字符，样式标记都是标点，行尾视为空白
+func nextRune(tokens []*inlineToken, i int) rune {
+	if i == len(tokens)-1 {
+		return ' '
+	}
+	if tokens[i+1].emphasis != nil {
+		return '*'
+	}
+	r, _ := utf8.DecodeRuneInString(tokens[i+1].text)
+	return r
+}
+
+// leftFlanking 开始标记能否被识别，见 https://spec.commonmark.org/0.31.2/#left-flanking-delimiter-run
+func leftFlanking(prev, next rune) bool {
+	return !unicode.IsSpace(next) && (!isPunctRune(next) || unicode.IsSpace(prev) || isPunctRune(prev))
+}
+
+// rightFlanking 结束标记能否被识别，见 https://spec.commonmark.org/0.31.2/#right-flanking-delimiter-run
+func rightFlanking(prev, next rune) bool {
+	return !unicode.IsSpace(prev) && (!isPunctRune(prev) || unicode.IsSpace(next) || isPunctRune(next))
+}
+
+func isPunctRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
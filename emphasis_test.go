@@ -0,0 +1,72 @@
+package lark_docx_md
+
+import (
+	"context"
+	"testing"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_TextMarkdownEmphasis(t *testing.T) {
+	type run struct {
+		content string
+		styles  string // b: 加粗，i: 斜体，s: 删除线，u: 下划线，c: 行内代码
+	}
+	textRun := func(r run) *larkdocx.TextElement {
+		style := larkdocx.NewTextElementStyleBuilder().
+			Bold(false).
+			InlineCode(false).
+			Italic(false).
+			Strikethrough(false).
+			Underline(false)
+		for _, c := range r.styles {
+			switch c {
+			case 'b':
+				style.Bold(true)
+			case 'i':
+				style.Italic(true)
+			case 's':
+				style.Strikethrough(true)
+			case 'u':
+				style.Underline(true)
+			case 'c':
+				style.InlineCode(true)
+			}
+		}
+		return larkdocx.NewTextElementBuilder().TextRun(
+			larkdocx.NewTextRunBuilder().Content(r.content).TextElementStyle(style.Build()).Build(),
+		).Build()
+	}
+
+	tests := []struct {
+		name string
+		runs []run
+		want string
+	}{
+		{"spaces inside bold", []run{{"a", ""}, {" bold ", "b"}, {"b", ""}}, "a **bold** b"},
+		{"spaces inside italic at edges", []run{{"  italic", "i"}}, "  *italic*"},
+		{"only spaces", []run{{"a", ""}, {"   ", "b"}, {"b", ""}}, "a   b"},
+		{"keep outer style open", []run{{"bold ", "b"}, {"both", "bi"}, {" bold", "b"}}, "**bold *both* bold**"},
+		{"close inner style first", []run{{"both", "bi"}, {" italic", "i"}}, "***both** italic*"},
+		{"reopen when order is unknown", []run{{"both", "bi"}, {" italic", "i"}, {" bold", "b"}}, "***both** italic* **bold**"},
+		{"reopen outer style", []run{{"italic ", "i"}, {"both", "bi"}, {" bold", "b"}}, "*italic **both*** **bold**"},
+		{"all styles", []run{{"all", "bisu"}}, "***~~<u>all</u>~~***"},
+		{"code inside bold", []run{{"run ", "b"}, {"go test", "bc"}}, "**run `go test`**"},
+		{"punctuation inside word", []run{{"a", ""}, {"(b)", "b"}, {"c", ""}}, "a<strong>(b)</strong>c"},
+		{"punctuation between spaces", []run{{"a ", ""}, {"(b)", "b"}, {" c", ""}}, "a **(b)** c"},
+		{"chinese punctuation", []run{{"中文", ""}, {"「引号」", "s"}, {"中文", ""}}, "中文<del>「引号」</del>中文"},
+		{"intraword italic", []run{{"foo", ""}, {"bar", "i"}, {"baz", ""}}, "foo*bar*baz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var elements []*larkdocx.TextElement
+			for _, r := range tt.runs {
+				elements = append(elements, textRun(r))
+			}
+			p := &DocxMarkdownProcessor{}
+			got := p.TextMarkdown(context.Background(), larkdocx.NewTextBuilder().Elements(elements).Build())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
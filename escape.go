@@ -0,0 +1,159 @@
+package lark_docx_md
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 文本在 Markdown 中的位置，不同位置需要转义的字符不同，可以组合使用
+const (
+	escapeTableCell = 1 << iota // 表格单元格：转义 |，换行转为 <br>
+	escapeHeading               // 标题：转义行尾的 #，换行转为空格
+	escapeLinkText              // 链接文本：不在行首，不需要转义块级语法
+)
+
+type escapeKey struct{}
+
+// withEscape 标记之后的文本所在的位置
+func withEscape(ctx context.Context, mode int) context.Context {
+	return context.WithValue(ctx, escapeKey{}, escapeMode(ctx)|mode)
+}
+
+func escapeMode(ctx context.Context) int {
+	mode, _ := ctx.Value(escapeKey{}).(int)
+	return mode
+}
+
+var (
+	// 行首会被识别为块级语法的文本：标题、引用、列表、分割线
+	lineStartRegexp = regexp.MustCompile(`^([ \t]*)([#>]|[-+*](?:[ \t]|$)|[-_*=]{3,}[ \t]*$|\d{1,9}[.)](?:[ \t]|$))`)
+	// HTML 标签、注释和自动链接的开始
+	htmlRegexp = regexp.MustCompile(`^<[A-Za-z/!?]`)
+	// HTML 实体
+	entityRegexp = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]*);`)
+)
+
+// escapeText 转义文本中的 Markdown 语法，lineStart 表示文本是否位于行首
+func escapeText(ctx context.Context, s string, lineStart bool) string {
+	mode := escapeMode(ctx)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = escapeInline(line)
+		if mode&escapeLinkText == 0 && (i > 0 || lineStart) {
+			lines[i] = escapeLineStart(lines[i])
+		}
+		if mode&escapeTableCell != 0 {
+			lines[i] = strings.ReplaceAll(lines[i], "|", `\|`)
+		}
+	}
+	if mode&escapeHeading != 0 {
+		last := len(lines) - 1
+		lines[last] = escapeClosingHashes(lines[last])
+	}
+	return strings.Join(lines, lineBreak(mode))
+}
+
+// lineBreak 文本中换行的输出方式，表格和标题只能在一行中
+func lineBreak(mode int) string {
+	switch {
+	case mode&escapeTableCell != 0:
+		return "<br>"
+	case mode&escapeHeading != 0:
+		return " "
+	default:
+		return "\n"
+	}
+}
+
+// escapeInline 转义行内语法：强调、代码、链接、HTML 和实体
+func escapeInline(s string) string {
+	runes := []rune(s)
+	buf := new(strings.Builder)
+	for i, r := range runes {
+		switch r {
+		case '\\', '`', '*', '[', ']', '~':
+			buf.WriteRune('\\')
+		case '_':
+			// 单词内的下划线不会被识别为强调，eg. snake_case
+			if i == 0 || i == len(runes)-1 || !isWordRune(runes[i-1]) || !isWordRune(runes[i+1]) {
+				buf.WriteRune('\\')
+			}
+		case '<':
+			if htmlRegexp.MatchString(string(runes[i:])) {
+				buf.WriteRune('\\')
+			}
+		case '&':
+			if entityRegexp.MatchString(string(runes[i:])) {
+				buf.WriteRune('\\')
+			}
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// escapeLineStart 转义行首的块级语法，eg. # 标题、> 引用、- 列表、1. 有序列表
+func escapeLineStart(line string) string {
+	m := lineStartRegexp.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line
+	}
+	// 有序列表转义数字后的 . 或 )，其余转义第一个字符
+	start := m[4]
+	if c := line[start]; c >= '0' && c <= '9' {
+		start = strings.IndexAny(line[start:], ".)") + start
+	}
+	return line[:start] + `\` + line[start:]
+}
+
+// escapeClosingHashes 转义标题行尾会被识别为结束标记的 #
+func escapeClosingHashes(line string) string {
+	trimmed := strings.TrimRight(line, " \t")
+	hashes := strings.TrimRight(trimmed, "#")
+	if hashes == trimmed || (hashes != "" && !strings.HasSuffix(hashes, " ") && !strings.HasSuffix(hashes, "\t")) {
+		return line
+	}
+	return hashes + `\` + line[len(hashes):]
+}
+
+// codeSpan 输出行内代码，反引号的数量多于内容中最长的连续反引号
+func codeSpan(ctx context.Context, code string) string {
+	mode := escapeMode(ctx)
+	code = strings.ReplaceAll(code, "\n", " ")
+	if mode&escapeTableCell != 0 {
+		// 表格中代码内的 | 也需要转义
+		code = strings.ReplaceAll(code, "|", `\|`)
+	}
+
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") ||
+		(strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") && strings.TrimSpace(code) != "") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// escapeURL 链接地址包含空格或括号时使用 <> 包裹
+func escapeURL(url string) string {
+	if !strings.ContainsAny(url, " ()<>") {
+		return url
+	}
+	return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(url) + ">"
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
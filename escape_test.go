@@ -0,0 +1,84 @@
+package lark_docx_md
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeText(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		s    string
+		want string
+	}{
+		{"plain", ctx, "普通文本 plain text", "普通文本 plain text"},
+		{"emphasis", ctx, "*args and **kwargs", `\*args and \*\*kwargs`},
+		{"underscore", ctx, "snake_case _private_ 中_文", `snake_case \_private\_ 中_文`},
+		{"strikethrough", ctx, "~~not deleted~~", `\~\~not deleted\~\~`},
+		{"code", ctx, "use `go test`", "use \\`go test\\`"},
+		{"backslash", ctx, `C:\Users\`, `C:\\Users\\`},
+		{"link", ctx, "[not a link](https://github.com/)", `\[not a link\](https://github.com/)`},
+		{"image", ctx, "![alt](x.png)", `!\[alt\](x.png)`},
+		{"html", ctx, "<div>content</div>", `\<div>content\</div>`},
+		{"html comment", ctx, "<!-- comment -->", `\<!-- comment -->`},
+		{"less than", ctx, "a < b && b > c", "a < b && b > c"},
+		{"entity", ctx, "&copy; &#169; AT&T", `\&copy; \&#169; AT&T`},
+		{"heading", ctx, "#hashtag", `\#hashtag`},
+		{"heading not line start", ctx, "issue #1", "issue #1"},
+		{"quote", ctx, "> not a quote", `\> not a quote`},
+		{"bullet", ctx, "- not a list", `\- not a list`},
+		{"plus bullet", ctx, "+ not a list", `\+ not a list`},
+		{"star bullet", ctx, "* not a list", `\* not a list`},
+		{"ordered", ctx, "1. not a list", `1\. not a list`},
+		{"ordered paren", ctx, "2024) not a list", `2024\) not a list`},
+		{"number", ctx, "3.14 is pi", "3.14 is pi"},
+		{"thematic break", ctx, "---", `\---`},
+		{"setext heading", ctx, "===", `\===`},
+		{"multiline", ctx, "first\n# second\n1. third", "first\n\\# second\n1\\. third"},
+		{"table cell", withEscape(ctx, escapeTableCell), "a | b\nc", `a \| b<br>c`},
+		{"heading closing hashes", withEscape(ctx, escapeHeading), "title ##", `title \##`},
+		{"heading only hashes", withEscape(ctx, escapeHeading), "###", `\###`},
+		{"heading csharp", withEscape(ctx, escapeHeading), "C#", "C#"},
+		{"heading newline", withEscape(ctx, escapeHeading), "first\nsecond", "first second"},
+		{"link text", withEscape(ctx, escapeLinkText), "# [1] - note", `# \[1\] - note`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeText(tt.ctx, tt.s, true))
+		})
+	}
+}
+
+func TestCodeSpan(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code string
+		want string
+	}{
+		{"plain", ctx, "*args", "`*args`"},
+		{"backtick", ctx, "a`b", "``a`b``"},
+		{"backticks", ctx, "a``b`c", "```a``b`c```"},
+		{"starts with backtick", ctx, "`x", "`` `x ``"},
+		{"surrounded by spaces", ctx, " x ", "`  x  `"},
+		{"only spaces", ctx, "  ", "`  `"},
+		{"newline", ctx, "a\nb", "`a b`"},
+		{"table cell", withEscape(ctx, escapeTableCell), "a || b", "`a \\|\\| b`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, codeSpan(tt.ctx, tt.code))
+		})
+	}
+}
+
+func TestEscapeURL(t *testing.T) {
+	assert.Equal(t, "https://github.com/", escapeURL("https://github.com/"))
+	assert.Equal(t, "<https://example.com/a b(1)>", escapeURL("https://example.com/a b(1)"))
+	assert.Equal(t, "<https://example.com/%3Cx%3E>", escapeURL("https://example.com/<x>"))
+}
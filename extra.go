@@ -0,0 +1,105 @@
+package lark_docx_md
+
+import (
+	"encoding/json"
+
+	"github.com/samber/lo"
+)
+
+// blockExtra SDK 尚未定义的块属性，从接口原始响应中解析
+type blockExtra struct {
+	BlockId *string     `json:"block_id,omitempty"`
+	Image   *imageExtra `json:"image,omitempty"`
+	Ordered *textExtra  `json:"ordered,omitempty"`
+	Code    *codeExtra  `json:"code,omitempty"`
+
+	LinkPreview     *linkPreviewExtra     `json:"link_preview,omitempty"`
+	ReferenceSynced *referenceSyncedExtra `json:"reference_synced,omitempty"`
+}
+
+type captionExtra struct {
+	Content *string `json:"content,omitempty"` // 描述
+}
+
+type textExtra struct {
+	Style *struct {
+		Sequence *string `json:"sequence,omitempty"` // 有序列表编号，eg. auto, 1, 2
+	} `json:"style,omitempty"`
+}
+
+type imageExtra struct {
+	Caption *captionExtra `json:"caption,omitempty"` // 图片描述
+}
+
+type codeExtra struct {
+	Caption *captionExtra `json:"caption,omitempty"` // 代码块标题，eg. 文件名
+}
+
+type linkPreviewExtra struct {
+	Url *string `json:"url,omitempty"` // 链接地址，需要 url decode
+}
+
+type referenceSyncedExtra struct {
+	SourceDocumentId *string `json:"source_document_id,omitempty"` // 源同步块所在的文档
+	SourceBlockId    *string `json:"source_block_id,omitempty"`    // 源同步块
+}
+
+// parseBlockExtras 从获取文档所有块接口的原始响应中解析块属性
+func parseBlockExtras(rawBody []byte) map[string]*blockExtra {
+	var body struct {
+		Data struct {
+			Items []*blockExtra `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return nil
+	}
+
+	extras := make(map[string]*blockExtra, len(body.Data.Items))
+	for _, item := range body.Data.Items {
+		if item.BlockId != nil {
+			extras[*item.BlockId] = item
+		}
+	}
+	return extras
+}
+
+// imageCaption 返回图片描述
+func (e *blockExtra) imageCaption() string {
+	if e.Image == nil || e.Image.Caption == nil {
+		return ""
+	}
+	return lo.FromPtr(e.Image.Caption.Content)
+}
+
+// orderedSequence 返回有序列表编号，auto 表示延续上一项
+func (e *blockExtra) orderedSequence() string {
+	if e.Ordered == nil || e.Ordered.Style == nil {
+		return ""
+	}
+	return lo.FromPtr(e.Ordered.Style.Sequence)
+}
+
+// codeCaption 返回代码块标题
+func (e *blockExtra) codeCaption() string {
+	if e.Code == nil || e.Code.Caption == nil {
+		return ""
+	}
+	return lo.FromPtr(e.Code.Caption.Content)
+}
+
+// linkPreviewURL 返回链接预览的地址
+func (e *blockExtra) linkPreviewURL() string {
+	if e.LinkPreview == nil {
+		return ""
+	}
+	return UnescapeUrl(lo.FromPtr(e.LinkPreview.Url))
+}
+
+// syncedSource 返回引用同步块对应的源文档和源同步块
+func (e *blockExtra) syncedSource() (string, string) {
+	if e.ReferenceSynced == nil {
+		return "", ""
+	}
+	return lo.FromPtr(e.ReferenceSynced.SourceDocumentId), lo.FromPtr(e.ReferenceSynced.SourceBlockId)
+}
@@ -0,0 +1,183 @@
+package lark_docx_md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+	"github.com/samber/lo"
+)
+
+var gitCommitVerbs = map[string]string{
+	SyncAdded:   "Add",
+	SyncUpdated: "Update",
+	SyncDeleted: "Delete",
+}
+
+// GitCommitter 将每个文档的变更提交到本地 git 仓库，不依赖 git 命令
+// 提交的作者和时间取自文档的最后编辑者和最后编辑时间，获取失败时使用默认作者和当前时间
+type GitCommitter struct {
+	LarkClient   *lark.Client // lark 客户端
+	RepoDir      string       // git 仓库工作区目录
+	DefaultName  string       // 默认作者
+	DefaultEmail string       // 默认作者邮箱
+
+	users userCache // user open id -> 用户
+}
+
+func NewGitCommitter(client *lark.Client, repoDir string) *GitCommitter {
+	return &GitCommitter{
+		LarkClient:   client,
+		RepoDir:      repoDir,
+		DefaultName:  "lark_docx_md",
+		DefaultEmail: "lark_docx_md@users.noreply.github.com",
+	}
+}
+
+// NewGitSyncer 创建增量同步到本地 git 仓库的 Syncer，每个变化的文档创建一个提交
+func NewGitSyncer(client *lark.Client, repoDir, statePath string, opts ...Option) *Syncer {
+	s := NewSyncer(client, statePath, opts...)
+	s.Committer = NewGitCommitter(client, repoDir)
+	return s
+}
+
+func (c *GitCommitter) Commit(ctx context.Context, change *SyncChange) error {
+	repo, err := git.PlainOpenWithOptions(c.RepoDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	root, err := filepath.Abs(w.Filesystem.Root())
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for _, path := range change.Paths {
+		if rel, ok := c.relPath(root, path); ok {
+			paths = append(paths, rel)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	// 提交会包含整个暂存区，暂存区中有本次同步以外的改动时拒绝提交，避免把用户的改动提交为文档的变更
+	status, err := w.Status()
+	if err != nil {
+		return err
+	}
+	for file, s := range status {
+		if s.Staging != git.Unmodified && s.Staging != git.Untracked && !containsPath(paths, file) {
+			return fmt.Errorf("git index has staged changes outside the synced document: %s", file)
+		}
+	}
+
+	for _, rel := range paths {
+		if _, err := w.Add(rel); err != nil && !errors.Is(err, index.ErrEntryNotFound) {
+			return fmt.Errorf("git add %s fail: %w", rel, err)
+		}
+	}
+
+	author := c.author(ctx, change)
+	msg := fmt.Sprintf("%s %s\n\nlark document: %s", gitCommitVerbs[change.Kind], paths[0], change.Token)
+	if change.RevisionId != 0 {
+		msg += fmt.Sprintf("\nrevision: %d", change.RevisionId)
+	}
+	_, err = w.Commit(msg, &git.CommitOptions{
+		Author:    author,
+		Committer: &object.Signature{Name: c.DefaultName, Email: c.DefaultEmail, When: time.Now()},
+	})
+	if errors.Is(err, git.ErrEmptyCommit) {
+		return nil
+	}
+	return err
+}
+
+// relPath 返回相对于仓库根目录的路径，不在仓库中的路径返回 false
+func (c *GitCommitter) relPath(root, path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// containsPath 文件是否是 paths 中的文件或在 paths 中的目录下，路径都相对于仓库根目录
+func containsPath(paths []string, file string) bool {
+	return lo.SomeBy(paths, func(path string) bool {
+		return file == path || strings.HasPrefix(file, path+"/")
+	})
+}
+
+// author 获取文档的最后编辑者和最后编辑时间作为提交作者
+func (c *GitCommitter) author(ctx context.Context, change *SyncChange) *object.Signature {
+	author := &object.Signature{Name: c.DefaultName, Email: c.DefaultEmail, When: time.Now()}
+	if change.Kind == SyncDeleted || c.LarkClient == nil {
+		return author
+	}
+
+	req := larkdrive.NewBatchQueryMetaReqBuilder().
+		UserIdType("open_id").
+		MetaRequest(
+			larkdrive.NewMetaRequestBuilder().
+				RequestDocs([]*larkdrive.RequestDoc{
+					larkdrive.NewRequestDocBuilder().DocToken(change.DocumentId).DocType(Docx).Build(),
+				}).
+				Build(),
+		).
+		Build()
+	resp, err := c.LarkClient.Drive.V1.Meta.BatchQuery(ctx, req)
+	if err != nil {
+		log.Printf("lark batch query document %s meta fail: %s", change.DocumentId, err)
+		return author
+	}
+	if !resp.Success() || len(resp.Data.Metas) == 0 {
+		log.Printf("lark batch query document %s meta fail: code:%d, msg:%s, requestId:%s", change.DocumentId, resp.Code, resp.Msg, resp.RequestId())
+		return author
+	}
+
+	meta := resp.Data.Metas[0]
+	if sec, err := strconv.ParseInt(lo.FromPtr(meta.LatestModifyTime), 10, 64); err == nil {
+		author.When = time.Unix(sec, 0)
+	}
+	if user := c.user(ctx, lo.FromPtr(meta.LatestModifyUser)); user != nil {
+		author.Name, author.Email = user.Name, user.Email
+	}
+	return author
+}
+
+// user 获取用户姓名和邮箱，获取失败时使用 open id
+func (c *GitCommitter) user(ctx context.Context, openId string) *object.Signature {
+	if openId == "" {
+		return nil
+	}
+	author := &object.Signature{Name: openId, Email: openId + "@open.feishu.cn"}
+	if user := c.users.user(ctx, c.LarkClient, openId); user != nil {
+		if lo.FromPtr(user.Name) != "" {
+			author.Name = *user.Name
+		}
+		if lo.FromPtr(user.Email) != "" {
+			author.Email = *user.Email
+		}
+	}
+	return author
+}
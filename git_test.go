@@ -0,0 +1,167 @@
+package lark_docx_md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitCommitter_Commit(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	addSyncDocument(server, "doc1", 1)
+	addSyncDocument(server, "doc2", 1)
+	editedAt := map[string]time.Time{
+		"张三": time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		"李四": time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC),
+	}
+	server.AddMeta(larkdrive.NewMetaBuilder().DocToken("doc1").DocType(Docx).LatestModifyUser("ou_1").LatestModifyTime("1714564800").Build())
+	server.AddMeta(larkdrive.NewMetaBuilder().DocToken("doc2").DocType(Docx).LatestModifyUser("ou_2").LatestModifyTime("1714651200").Build())
+	server.AddUser("ou_1", "张三")
+	server.AddUser("ou_2", "李四")
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	assert.NoError(t, err)
+
+	s := NewGitSyncer(server.Client(), dir, filepath.Join(dir, ".sync.json"))
+	docs := []SyncDocument{
+		{Docx, "doc1", filepath.Join(dir, "docs", "doc1.md")},
+		{Docx, "doc2", filepath.Join(dir, "docs", "doc2.md")},
+	}
+	_, err = s.Sync(context.Background(), docs)
+	assert.NoError(t, err)
+
+	// 版本未变化时不产生提交
+	_, err = s.Sync(context.Background(), docs)
+	assert.NoError(t, err)
+
+	server.SetRevision("doc2", 2)
+	_, err = s.Sync(context.Background(), docs[1:])
+	assert.NoError(t, err)
+
+	iter, err := repo.Log(&git.LogOptions{})
+	assert.NoError(t, err)
+	var messages, authors []string
+	_ = iter.ForEach(func(c *object.Commit) error {
+		messages = append(messages, c.Message)
+		authors = append(authors, c.Author.Name)
+		if when, ok := editedAt[c.Author.Name]; ok {
+			assert.True(t, when.Equal(c.Author.When))
+		}
+		return nil
+	})
+	assert.Equal(t, []string{
+		"Delete docs/doc1.md\n\nlark document: doc1",
+		"Update docs/doc2.md\n\nlark document: doc2\nrevision: 2",
+		"Add docs/doc2.md\n\nlark document: doc2\nrevision: 1",
+		"Add docs/doc1.md\n\nlark document: doc1\nrevision: 1",
+	}, messages)
+	// 删除文档时没有最后编辑者，使用默认作者
+	assert.Equal(t, []string{"lark_docx_md", "李四", "李四", "张三"}, authors)
+
+	// 工作区没有未提交的改动
+	w, _ := repo.Worktree()
+	status, err := w.Status()
+	assert.NoError(t, err)
+	assert.True(t, status.IsClean(), status.String())
+	_, err = os.Stat(filepath.Join(dir, "docs", "doc1.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGitCommitter_author(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.AddMeta(larkdrive.NewMetaBuilder().DocToken("doc1").DocType(Docx).LatestModifyUser("ou_1").LatestModifyTime("1714564800").Build())
+	server.AddMeta(larkdrive.NewMetaBuilder().DocToken("doc2").DocType(Docx).LatestModifyUser("ou_2").LatestModifyTime("1714568400").Build())
+	server.AddMeta(larkdrive.NewMetaBuilder().DocToken("doc3").DocType(Docx).LatestModifyUser("ou_3").LatestModifyTime("1714572000").Build())
+	server.AddUser("ou_1", "张三")
+	server.SetUserEmail("ou_1", "zhangsan@example.com")
+	server.AddUser("ou_2", "李四")
+
+	c := NewGitCommitter(server.Client(), t.TempDir())
+	author := func(kind, documentId string) *object.Signature {
+		return c.author(context.Background(), &SyncChange{Kind: kind, Token: documentId, DocumentId: documentId})
+	}
+
+	got := author(SyncAdded, "doc1")
+	assert.Equal(t, "张三", got.Name)
+	assert.Equal(t, "zhangsan@example.com", got.Email)
+	assert.True(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Equal(got.When))
+
+	// 用户没有邮箱时使用 open id 作为邮箱，获取用户失败时使用 open id 作为作者
+	got = author(SyncUpdated, "doc2")
+	assert.Equal(t, "李四", got.Name)
+	assert.Equal(t, "ou_2@open.feishu.cn", got.Email)
+	got = author(SyncUpdated, "doc3")
+	assert.Equal(t, "ou_3", got.Name)
+	assert.Equal(t, "ou_3@open.feishu.cn", got.Email)
+	assert.True(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC).Equal(got.When))
+
+	// 获取元数据失败或文档已删除时使用默认作者
+	for _, got := range []*object.Signature{author(SyncUpdated, "doc4"), author(SyncDeleted, "doc1")} {
+		assert.Equal(t, c.DefaultName, got.Name)
+		assert.Equal(t, c.DefaultEmail, got.Email)
+	}
+
+	// 用户信息被缓存
+	assert.Equal(t, "张三", author(SyncUpdated, "doc1").Name)
+	assert.Equal(t, 1, lo.Count(server.Requests(), "GET /open-apis/contact/v3/users/ou_1"))
+	assert.Equal(t, 1, lo.Count(server.Requests(), "GET /open-apis/contact/v3/users/ou_3"))
+}
+
+func TestGitCommitter_CommitStaged(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	addSyncDocument(server, "doc1", 1)
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	assert.NoError(t, err)
+	w, _ := repo.Worktree()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o666))
+	_, err = w.Add("notes.txt")
+	assert.NoError(t, err)
+
+	// 暂存区中有其他改动时不提交
+	s := NewGitSyncer(server.Client(), dir, filepath.Join(dir, ".sync.json"))
+	docs := []SyncDocument{{Docx, "doc1", filepath.Join(dir, "docs", "doc1.md")}}
+	_, err = s.Sync(context.Background(), docs)
+	assert.ErrorContains(t, err, "notes.txt")
+	_, err = repo.Head()
+	assert.Error(t, err)
+	status, _ := w.Status()
+	assert.Equal(t, git.Added, status.File("notes.txt").Staging)
+
+	// 其他改动提交后继续同步，提交中不包含其他文件
+	_, err = w.Commit("Add notes", &git.CommitOptions{Author: &object.Signature{Name: "user", Email: "user@example.com", When: time.Now()}})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes v2"), 0o666))
+	server.SetRevision("doc1", 2)
+	_, err = s.Sync(context.Background(), docs)
+	assert.NoError(t, err)
+
+	head, _ := repo.Head()
+	commit, _ := repo.CommitObject(head.Hash())
+	assert.Equal(t, "Update docs/doc1.md\n\nlark document: doc1\nrevision: 2", commit.Message)
+	parent, err := commit.Parent(0)
+	assert.NoError(t, err)
+	patch, err := parent.Patch(commit)
+	assert.NoError(t, err)
+	var files []string
+	for _, stat := range patch.Stats() {
+		files = append(files, stat.Name)
+	}
+	assert.ElementsMatch(t, []string{".sync.json", "docs/doc1.md"}, files)
+	status, _ = w.Status()
+	assert.Equal(t, git.Modified, status.File("notes.txt").Worktree)
+}
@@ -0,0 +1,46 @@
+package lark_docx_md
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 重新生成 testdata/golden 下的 Markdown：go test -run TestGolden -update
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenOptions 转换 golden 文件使用的选项，未配置时使用默认选项
+var goldenOptions = map[string][]Option{
+	"toc_details":         {UseTOC(0, 0, ""), UseFoldedDetails()},
+	"toc_details_anchors": {UseTOC(0, 0, ""), UseFoldedDetails(), UseAnchors(AnchorHeading)},
+}
+
+// TestGolden testdata/golden 下每个 .json 文件是获取文档所有块接口返回的块列表，格式见 ConvertJSON，同名的 .md 文件是期望的 Markdown
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			assert.NoError(t, err)
+			got, err := ConvertJSON(data, goldenOptions[name]...)
+			assert.NoError(t, err)
+
+			golden := strings.TrimSuffix(file, ".json") + ".md"
+			if *update {
+				assert.NoError(t, os.WriteFile(golden, []byte(got), 0o666))
+				return
+			}
+			want, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), got)
+		})
+	}
+}
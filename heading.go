@@ -0,0 +1,64 @@
+package lark_docx_md
+
+import (
+	"encoding/json"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// headingLevel 返回标题块在 Markdown 中的级别，可能超过六级，偏移为负数时最小为一级
+func (p *DocxMarkdownProcessor) headingLevel(blockType int) int {
+	if level := blockType - 2 + p.config().HeadingOffset; level > 1 {
+		return level
+	}
+	return 1
+}
+
+// isMarkdownHeading 标题块是否输出为 Markdown 标题，超过六级的标题按照 DeepHeading 输出时不是
+func (p *DocxMarkdownProcessor) isMarkdownHeading(blockType int) bool {
+	if blockType == Page {
+		return !p.config().TitleFrontMatter
+	}
+	return p.headingLevel(blockType) <= 6 || p.config().DeepHeading == ""
+}
+
+// titleFrontMatter 输出包含文档标题的 front matter
+func titleFrontMatter(title string) string {
+	// JSON 字符串是合法的 YAML 双引号字符串
+	quoted, _ := json.Marshal(title)
+	return "---\ntitle: " + string(quoted) + "\n---"
+}
+
+// boldText 返回所有文本都加粗的文本
+func boldText(text *larkdocx.Text) *larkdocx.Text {
+	if text == nil {
+		return nil
+	}
+	bold := *text
+	bold.Elements = make([]*larkdocx.TextElement, 0, len(text.Elements))
+	for _, e := range text.Elements {
+		e := *e
+		switch {
+		case e.TextRun != nil:
+			run := *e.TextRun
+			run.TextElementStyle = withBold(run.TextElementStyle)
+			e.TextRun = &run
+		case e.MentionDoc != nil:
+			doc := *e.MentionDoc
+			doc.TextElementStyle = withBold(doc.TextElementStyle)
+			e.MentionDoc = &doc
+		}
+		bold.Elements = append(bold.Elements, &e)
+	}
+	return &bold
+}
+
+func withBold(style *larkdocx.TextElementStyle) *larkdocx.TextElementStyle {
+	bold := larkdocx.TextElementStyle{}
+	if style != nil {
+		bold = *style
+	}
+	bold.Bold = lo.ToPtr(true)
+	return &bold
+}
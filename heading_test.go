@@ -0,0 +1,58 @@
+package lark_docx_md
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockHeadingMarkdownStrategy(t *testing.T) {
+	text := larkdocx.NewTextBuilder().Elements([]*larkdocx.TextElement{
+		larkdocx.NewTextElementBuilder().TextRun(larkdocx.NewTextRunBuilder().Content("A & ").Build()).Build(),
+		larkdocx.NewTextElementBuilder().TextRun(larkdocx.NewTextRunBuilder().Content("B").
+			TextElementStyle(larkdocx.NewTextElementStyleBuilder().Italic(true).Build()).Build()).Build(),
+	}).Build()
+	heading1 := larkdocx.NewBlockBuilder().BlockType(Heading1).Heading1(text).Build()
+	heading6 := larkdocx.NewBlockBuilder().BlockType(Heading6).Heading6(text).Build()
+	heading8 := larkdocx.NewBlockBuilder().BlockType(Heading8).Heading8(text).Build()
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		block *larkdocx.Block
+		want  string
+	}{
+		{"default", nil, heading1, "# A & *B*"},
+		{"clamp", nil, heading8, "###### A & *B*"},
+		{"offset", []Option{UseHeadingOffset(1)}, heading1, "## A & *B*"},
+		{"offset clamp", []Option{UseHeadingOffset(1)}, heading6, "###### A & *B*"},
+		{"negative offset", []Option{UseHeadingOffset(-1)}, heading6, "##### A & *B*"},
+		{"negative offset clamp", []Option{UseHeadingOffset(-3)}, heading1, "# A & *B*"},
+		{"bold", []Option{UseDeepHeading(DeepHeadingBold)}, heading8, "**A & *B***"},
+		{"bold within six", []Option{UseDeepHeading(DeepHeadingBold)}, heading6, "###### A & *B*"},
+		{"bold offset", []Option{UseHeadingOffset(1), UseDeepHeading(DeepHeadingBold)}, heading6, "**A & *B***"},
+		{"html", []Option{UseDeepHeading(DeepHeadingHTML)}, heading8, `<h6 data-level="8">A &amp; B</h6>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage", tt.opts...)
+			assert.Equal(t, tt.want, p.BlockHeadingMarkdown(context.Background(), tt.block))
+		})
+	}
+}
+
+func TestConvertJSON_TitleFrontMatter(t *testing.T) {
+	data := []byte(`[
+		{"block_id": "doxcnPage", "block_type": 1, "children": ["h1", "h7"], "page": {"elements": [{"text_run": {"content": "设计 \"v2\""}}]}},
+		{"block_id": "h1", "block_type": 3, "parent_id": "doxcnPage", "heading1": {"elements": [{"text_run": {"content": "背景"}}]}},
+		{"block_id": "h7", "block_type": 9, "parent_id": "doxcnPage", "heading7": {"elements": [{"text_run": {"content": "细节"}}]}}
+	]`)
+	got, err := ConvertJSON(data, UseTitleFrontMatter(), UseDeepHeading(DeepHeadingBold), UseTOC(0, 0, ""))
+	assert.NoError(t, err)
+	// 文档标题和加粗的标题不是 Markdown 标题，不在目录中
+	want := "---\ntitle: \"设计 \\\"v2\\\"\"\n---\n\n- [背景](#背景)\n\n# 背景\n\n**细节**\n\n***"
+	assert.True(t, strings.HasPrefix(got, want), got)
+}
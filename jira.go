@@ -0,0 +1,109 @@
+package lark_docx_md
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// JiraClient 通过 Jira REST API 获取 issue 的标题和状态
+type JiraClient struct {
+	BaseURL    string       // Jira 地址，eg. https://example.atlassian.net
+	Username   string       // 用户名或邮箱，不为空时使用 basic 认证，为空时使用 bearer 认证
+	Token      string       // API token 或 personal access token，为空时不认证
+	HTTPClient *http.Client // 为空时使用 http.DefaultClient
+	LinkOnly   bool         // 只输出 issue 链接，不请求 Jira 接口，适用于无法访问 Jira 接口的场景
+}
+
+// jiraIssue Jira issue 的标题和状态
+type jiraIssue struct {
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+func NewJiraClient(baseURL, username, token string) *JiraClient {
+	return &JiraClient{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Username: username,
+		Token:    token,
+	}
+}
+
+// BrowseURL 返回 issue 的页面地址
+func (c *JiraClient) BrowseURL(key string) string {
+	return fmt.Sprintf("%s/browse/%s", strings.TrimRight(c.BaseURL, "/"), url.PathEscape(key))
+}
+
+func (c *JiraClient) issue(ctx context.Context, key string) (*jiraIssue, error) {
+	api := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status", strings.TrimRight(c.BaseURL, "/"), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.Token == "":
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+	issue := &jiraIssue{}
+	if err := json.NewDecoder(resp.Body).Decode(issue); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// BlockJiraIssueMarkdown Jira issue 输出为链接，eg. [PROJ-1 标题](https://example.atlassian.net/browse/PROJ-1) (In Progress)
+// 只输出链接或无法获取 issue 时输出 issue key 的链接，没有配置 Jira 地址时无法生成链接，只输出 issue key
+func (p *DocxMarkdownProcessor) BlockJiraIssueMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	if block.JiraIssue == nil || lo.FromPtr(block.JiraIssue.Key) == "" {
+		return "<!-- empty jira issue -->"
+	}
+	key := *block.JiraIssue.Key
+	jira := p.config().Jira
+	if jira == nil || jira.BaseURL == "" {
+		return escapeText(ctx, key, true)
+	}
+
+	linkCtx := withEscape(ctx, escapeLinkText)
+	link := fmt.Sprintf("[%s](%s)", escapeText(linkCtx, key, false), escapeURL(jira.BrowseURL(key)))
+	if jira.LinkOnly {
+		return link
+	}
+	issue, err := jira.issue(ctx, key)
+	if err != nil {
+		log.Printf("jira get issue %s fail: %s", key, err)
+		return link
+	}
+	text := fmt.Sprintf("[%s](%s)", escapeText(linkCtx, strings.TrimSpace(key+" "+issue.Fields.Summary), false), escapeURL(jira.BrowseURL(key)))
+	if status := issue.Fields.Status.Name; status != "" {
+		text += fmt.Sprintf(" (%s)", escapeText(ctx, status, false))
+	}
+	return text
+}
@@ -0,0 +1,83 @@
+package lark_docx_md
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockJiraIssueMarkdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, ok := r.BasicAuth()
+		if !ok || user != "bot@example.com" || token != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/rest/api/2/issue/PROJ-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"PROJ-1","fields":{"summary":"导出 [Jira]","status":{"name":"In Progress"}}}`))
+	}))
+	defer server.Close()
+
+	issue := func(key string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().
+			BlockType(JiraIssue).
+			JiraIssue(larkdocx.NewJiraIssueBuilder().Id("10001").Key(key).Build()).
+			Build()
+	}
+	tests := []struct {
+		name  string
+		opts  []Option
+		block *larkdocx.Block
+		want  string
+	}{
+		{
+			"issue",
+			[]Option{UseJira(NewJiraClient(server.URL+"/", "bot@example.com", "secret"))},
+			issue("PROJ-1"),
+			"[PROJ-1 导出 \\[Jira\\]](" + server.URL + "/browse/PROJ-1) (In Progress)",
+		},
+		{
+			"not found",
+			[]Option{UseJira(NewJiraClient(server.URL, "bot@example.com", "secret"))},
+			issue("PROJ-2"),
+			"[PROJ-2](" + server.URL + "/browse/PROJ-2)",
+		},
+		{
+			"unauthorized",
+			[]Option{UseJira(NewJiraClient(server.URL, "", "secret"))},
+			issue("PROJ-1"),
+			"[PROJ-1](" + server.URL + "/browse/PROJ-1)",
+		},
+		{
+			"link only",
+			[]Option{UseJira(&JiraClient{BaseURL: server.URL, LinkOnly: true})},
+			issue("PROJ-1"),
+			"[PROJ-1](" + server.URL + "/browse/PROJ-1)",
+		},
+		{
+			"without jira",
+			nil,
+			issue("PROJ-1"),
+			"PROJ-1",
+		},
+		{
+			"without issue",
+			[]Option{UseJira(NewJiraClient(server.URL, "bot@example.com", "secret"))},
+			larkdocx.NewBlockBuilder().BlockType(JiraIssue).Build(),
+			"<!-- empty jira issue -->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage", tt.opts...)
+			assert.Equal(t, tt.want, p.BlockJiraIssueMarkdown(context.Background(), tt.block))
+		})
+	}
+}
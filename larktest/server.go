@@ -0,0 +1,502 @@
+// Package larktest 提供模拟 Lark 开放平台接口的 HTTP 服务，用于在不访问真实接口、不使用 monkey patch 的情况下进行端到端测试
+package larktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+	larktask "github.com/larksuite/oapi-sdk-go/v3/service/task/v2"
+	"github.com/samber/lo"
+)
+
+const (
+	codeNotFound     = 1770002 // 资源不存在
+	codeInvalidParam = 1770001 // 参数错误
+)
+
+// Server 模拟的 Lark 开放平台，支持以下接口：
+//   - 获取 tenant_access_token
+//   - 获取文档信息、分页获取文档所有块
+//   - 获取知识空间节点信息
+//   - 下载素材、获取素材临时下载链接
+//   - 导出画板为图片
+//   - 获取任务详情
+//   - 获取群信息
+//   - 获取文档评论及其回复、获取用户信息
+//   - 获取文档元数据
+type Server struct {
+	*httptest.Server
+	PageSize int // 获取文档所有块、评论的回复时每页最多返回的数量，为 0 时使用请求中的 page_size
+
+	mu        sync.Mutex
+	documents map[string]*document                // document id -> 文档
+	wikiNodes map[string]string                   // wiki token -> document id
+	medias    map[string][]byte                   // file token -> 素材内容
+	boards    map[string][]byte                   // whiteboard token -> 画板图片
+	tasks     map[string]*larktask.Task           // task guid -> 任务
+	chats     map[string]string                   // chat id -> 群名称
+	comments  map[string][]*larkdrive.FileComment // file token -> 评论
+	users     map[string]*user                    // user open id -> 用户
+	metas     map[string]*larkdrive.Meta          // doc token -> 文档元数据
+	requests  []string
+}
+
+type user struct {
+	Name  string
+	Email string
+}
+
+type document struct {
+	RevisionId int
+	Title      string
+	Blocks     []json.RawMessage
+}
+
+func NewServer() *Server {
+	s := &Server{
+		documents: make(map[string]*document),
+		wikiNodes: make(map[string]string),
+		medias:    make(map[string][]byte),
+		boards:    make(map[string][]byte),
+		tasks:     make(map[string]*larktask.Task),
+		chats:     make(map[string]string),
+		comments:  make(map[string][]*larkdrive.FileComment),
+		users:     make(map[string]*user),
+		metas:     make(map[string]*larkdrive.Meta),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", s.handleTenantAccessToken)
+	mux.HandleFunc("/open-apis/docx/v1/documents/", s.handleDocument)
+	mux.HandleFunc("/open-apis/wiki/v2/spaces/get_node", s.handleWikiNode)
+	mux.HandleFunc("/open-apis/drive/v1/medias/batch_get_tmp_download_url", s.handleTmpDownloadUrl)
+	mux.HandleFunc("/open-apis/drive/v1/medias/", s.handleMediaDownload)
+	mux.HandleFunc("/tmp/", s.handleTmpDownload)
+	mux.HandleFunc("/open-apis/board/v1/whiteboards/", s.handleBoardDownload)
+	mux.HandleFunc("/open-apis/task/v2/tasks/", s.handleTask)
+	mux.HandleFunc("/open-apis/im/v1/chats/", s.handleChat)
+	mux.HandleFunc("/open-apis/drive/v1/files/", s.handleComments)
+	mux.HandleFunc("/open-apis/contact/v3/users/", s.handleUser)
+	mux.HandleFunc("/open-apis/drive/v1/metas/batch_query", s.handleMetas)
+	s.Server = httptest.NewServer(s.record(mux))
+	return s
+}
+
+// Client 创建请求模拟服务的 lark 客户端
+func (s *Server) Client(options ...lark.ClientOptionFunc) *lark.Client {
+	options = append([]lark.ClientOptionFunc{lark.WithOpenBaseUrl(s.URL)}, options...)
+	return lark.NewClient("cli_larktest", "larktest_secret", options...)
+}
+
+// AddDocument 添加文档，blocks 的第一个块为文档的根块
+func (s *Server) AddDocument(documentId string, blocks ...*larkdocx.Block) {
+	raws := make([]json.RawMessage, 0, len(blocks))
+	for _, block := range blocks {
+		raw, _ := json.Marshal(block)
+		raws = append(raws, raw)
+	}
+	s.addDocument(documentId, raws)
+}
+
+// AddDocumentJSON 使用块列表的 JSON 添加文档，可以包含 SDK 尚未定义的块属性
+func (s *Server) AddDocumentJSON(documentId string, blocksJSON []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(blocksJSON, &raws); err != nil {
+		return err
+	}
+	s.addDocument(documentId, raws)
+	return nil
+}
+
+func (s *Server) addDocument(documentId string, blocks []json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.documents[documentId]
+	if !ok {
+		doc = &document{Title: documentId}
+		s.documents[documentId] = doc
+	}
+	doc.RevisionId++
+	doc.Blocks = blocks
+}
+
+// SetRevision 设置文档版本
+func (s *Server) SetRevision(documentId string, revisionId int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := s.documents[documentId]; ok {
+		doc.RevisionId = revisionId
+	}
+}
+
+// AddWikiNode 添加知识空间节点，节点指向 docx 文档
+func (s *Server) AddWikiNode(token, documentId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wikiNodes[token] = documentId
+}
+
+// AddMedia 添加素材，eg. 图片
+func (s *Server) AddMedia(token string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.medias[token] = data
+}
+
+// AddBoard 添加画板，image 为画板导出的图片
+func (s *Server) AddBoard(token string, image []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boards[token] = image
+}
+
+// AddTask 添加任务，使用任务的 guid 查询
+func (s *Server) AddTask(task *larktask.Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[*task.Guid] = task
+}
+
+// AddChat 添加群，使用群的 chat id 查询
+func (s *Server) AddChat(chatId, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats[chatId] = name
+}
+
+// AddComment 添加文档评论，PageSize 不为 0 时评论的回复分页返回
+func (s *Server) AddComment(fileToken string, comment *larkdrive.FileComment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.comments[fileToken] = append(s.comments[fileToken], comment)
+}
+
+// AddUser 添加用户，使用用户的 open id 查询
+func (s *Server) AddUser(openId, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.users[openId]; ok {
+		u.Name = name
+		return
+	}
+	s.users[openId] = &user{Name: name}
+}
+
+// SetUserEmail 设置用户邮箱，用户不存在时添加没有姓名的用户
+func (s *Server) SetUserEmail(openId, email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.users[openId]; ok {
+		u.Email = email
+		return
+	}
+	s.users[openId] = &user{Email: email}
+}
+
+// AddMeta 添加文档元数据，使用文档的 doc token 查询
+func (s *Server) AddMeta(meta *larkdrive.Meta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metas[*meta.DocToken] = meta
+}
+
+// Requests 返回收到的请求，格式为 "METHOD /path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleTenantAccessToken(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"code":                0,
+		"msg":                 "ok",
+		"tenant_access_token": "t-larktest",
+		"expire":              7200,
+	})
+}
+
+// handleDocument 处理 /open-apis/docx/v1/documents/:document_id[/blocks]
+func (s *Server) handleDocument(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/open-apis/docx/v1/documents/")
+	documentId, sub, _ := strings.Cut(path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, ok := s.documents[documentId]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, "document not found")
+		return
+	}
+
+	switch sub {
+	case "":
+		writeData(w, map[string]interface{}{
+			"document": map[string]interface{}{
+				"document_id": documentId,
+				"revision_id": doc.RevisionId,
+				"title":       doc.Title,
+			},
+		})
+	case "blocks":
+		s.listBlocks(w, r, doc)
+	default:
+		writeError(w, http.StatusNotFound, codeNotFound, "not found")
+	}
+}
+
+func (s *Server) listBlocks(w http.ResponseWriter, r *http.Request, doc *document) {
+	query := r.URL.Query()
+	start := 0
+	if token := query.Get("page_token"); token != "" {
+		var err error
+		if start, err = strconv.Atoi(token); err != nil || start > len(doc.Blocks) {
+			writeError(w, http.StatusBadRequest, codeInvalidParam, "invalid page_token")
+			return
+		}
+	}
+	size, _ := strconv.Atoi(query.Get("page_size"))
+	if size <= 0 || size > 500 {
+		size = 500
+	}
+	if s.PageSize > 0 && s.PageSize < size {
+		size = s.PageSize
+	}
+
+	end := start + size
+	if end > len(doc.Blocks) {
+		end = len(doc.Blocks)
+	}
+	data := map[string]interface{}{
+		"items":    doc.Blocks[start:end],
+		"has_more": end < len(doc.Blocks),
+	}
+	if end < len(doc.Blocks) {
+		data["page_token"] = strconv.Itoa(end)
+	}
+	writeData(w, data)
+}
+
+func (s *Server) handleWikiNode(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	documentId, ok := s.wikiNodes[token]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, "wiki node not found")
+		return
+	}
+	writeData(w, map[string]interface{}{
+		"node": map[string]interface{}{
+			"node_token": token,
+			"obj_token":  documentId,
+			"obj_type":   "docx",
+		},
+	})
+}
+
+func (s *Server) handleTmpDownloadUrl(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	urls := []map[string]string{}
+	for _, token := range r.URL.Query()["file_tokens"] {
+		if _, ok := s.medias[token]; !ok {
+			continue
+		}
+		urls = append(urls, map[string]string{
+			"file_token":       token,
+			"tmp_download_url": fmt.Sprintf("%s/tmp/%s", s.URL, token),
+		})
+	}
+	writeData(w, map[string]interface{}{"tmp_download_urls": urls})
+}
+
+// handleMediaDownload 处理 /open-apis/drive/v1/medias/:file_token/download
+func (s *Server) handleMediaDownload(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/open-apis/drive/v1/medias/")
+	if !strings.HasSuffix(path, "/download") {
+		writeError(w, http.StatusNotFound, codeNotFound, "not found")
+		return
+	}
+	s.writeMedia(w, strings.TrimSuffix(path, "/download"))
+}
+
+func (s *Server) handleTmpDownload(w http.ResponseWriter, r *http.Request) {
+	s.writeMedia(w, strings.TrimPrefix(r.URL.Path, "/tmp/"))
+}
+
+func (s *Server) writeMedia(w http.ResponseWriter, token string) {
+	s.mu.Lock()
+	data, ok := s.medias[token]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, "media not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", token))
+	_, _ = w.Write(data)
+}
+
+// handleBoardDownload 处理 /open-apis/board/v1/whiteboards/:whiteboard_id/download_as_image
+func (s *Server) handleBoardDownload(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/open-apis/board/v1/whiteboards/")
+	token, sub, _ := strings.Cut(path, "/")
+	if sub != "download_as_image" {
+		writeError(w, http.StatusNotFound, codeNotFound, "not found")
+		return
+	}
+
+	s.mu.Lock()
+	data, ok := s.boards[token]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, "whiteboard not found")
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	_, _ = w.Write(data)
+}
+
+// handleTask 处理 /open-apis/task/v2/tasks/:task_guid
+func (s *Server) handleTask(w http.ResponseWriter, r *http.Request) {
+	guid := strings.TrimPrefix(r.URL.Path, "/open-apis/task/v2/tasks/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[guid]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, "task not found")
+		return
+	}
+	writeData(w, map[string]interface{}{"task": task})
+}
+
+// handleChat 处理 /open-apis/im/v1/chats/:chat_id
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	chatId := strings.TrimPrefix(r.URL.Path, "/open-apis/im/v1/chats/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, ok := s.chats[chatId]
+	if !ok {
+		writeError(w, http.StatusBadRequest, codeNotFound, "chat not found")
+		return
+	}
+	writeData(w, map[string]interface{}{"name": name, "chat_mode": "group"})
+}
+
+// handleComments 处理 /open-apis/drive/v1/files/:file_token/comments 和 /open-apis/drive/v1/files/:file_token/comments/:comment_id/replies
+func (s *Server) handleComments(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/open-apis/drive/v1/files/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case len(parts) == 2 && parts[1] == "comments":
+		var items []*larkdrive.FileComment
+		for _, comment := range s.comments[parts[0]] {
+			item := *comment
+			if replies := lo.FromPtr(comment.ReplyList).Replies; s.PageSize > 0 && len(replies) > s.PageSize {
+				// 评论中只返回第一页回复
+				item.ReplyList = &larkdrive.ReplyList{Replies: replies[:s.PageSize]}
+				item.HasMore = lo.ToPtr(true)
+			}
+			items = append(items, &item)
+		}
+		writeData(w, map[string]interface{}{"items": items, "has_more": false})
+	case len(parts) == 4 && parts[1] == "comments" && parts[3] == "replies":
+		comment, ok := lo.Find(s.comments[parts[0]], func(c *larkdrive.FileComment) bool {
+			return lo.FromPtr(c.CommentId) == parts[2]
+		})
+		if !ok {
+			writeError(w, http.StatusNotFound, codeNotFound, "comment not found")
+			return
+		}
+		replies := lo.FromPtr(comment.ReplyList).Replies
+		start, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+		end := len(replies)
+		if s.PageSize > 0 && start+s.PageSize < end {
+			end = start + s.PageSize
+		}
+		writeData(w, map[string]interface{}{
+			"items":      replies[start:end],
+			"has_more":   end < len(replies),
+			"page_token": strconv.Itoa(end),
+		})
+	default:
+		writeError(w, http.StatusNotFound, codeNotFound, "not found")
+	}
+}
+
+// handleUser 处理 /open-apis/contact/v3/users/:user_id
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	openId := strings.TrimPrefix(r.URL.Path, "/open-apis/contact/v3/users/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[openId]
+	if !ok {
+		writeError(w, http.StatusBadRequest, codeNotFound, "user not found")
+		return
+	}
+	writeData(w, map[string]interface{}{"user": map[string]interface{}{"open_id": openId, "name": u.Name, "email": u.Email}})
+}
+
+// handleMetas 处理 /open-apis/drive/v1/metas/batch_query，不存在的文档放在 failed_list 中
+func (s *Server) handleMetas(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RequestDocs []*larkdrive.RequestDoc `json:"request_docs"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&body) != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidParam, "invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metas, failed := []*larkdrive.Meta{}, []*larkdrive.MetaFailed{}
+	for _, doc := range body.RequestDocs {
+		if meta, ok := s.metas[lo.FromPtr(doc.DocToken)]; ok {
+			metas = append(metas, meta)
+			continue
+		}
+		failed = append(failed, &larkdrive.MetaFailed{Token: doc.DocToken, Code: lo.ToPtr(codeNotFound)})
+	}
+	writeData(w, map[string]interface{}{"metas": metas, "failed_list": failed})
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, map[string]interface{}{"code": 0, "msg": "success", "data": data})
+}
+
+func writeError(w http.ResponseWriter, status, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "msg": msg})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
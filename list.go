@@ -0,0 +1,107 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// listItem 有序列表项的编号和分隔符
+type listItem struct {
+	number    int
+	delimiter string // . 或 )，编号不连续时切换分隔符开始新的列表
+}
+
+type listItemKey struct{}
+
+func withListItem(ctx context.Context, item listItem) context.Context {
+	return context.WithValue(ctx, listItemKey{}, item)
+}
+
+// orderedMarker 返回有序列表项的标记，eg. 1.
+func orderedMarker(ctx context.Context) string {
+	item, ok := ctx.Value(listItemKey{}).(listItem)
+	if !ok {
+		return "1."
+	}
+	return fmt.Sprintf("%d%s", item.number, item.delimiter)
+}
+
+// isListItem 块是否为列表项，待办事项和任务输出为任务列表
+func isListItem(node *Node) bool {
+	if node == nil || node.Block == nil {
+		return false
+	}
+	switch lo.FromPtr(node.BlockType) {
+	case Bullet, Ordered, Todo, Task, OkrObjective, OkrKeyResult:
+		return true
+	default:
+		return false
+	}
+}
+
+// nextListItem 按照有序列表的编号方式计算列表项编号
+// 编号为 auto 时延续上一个相邻的有序列表项，为数字时从该数字开始
+func (p *DocxMarkdownProcessor) nextListItem(node, prev *Node, prevItem listItem) listItem {
+	continued := prev != nil && lo.FromPtr(prev.BlockType) == Ordered
+	item := listItem{number: 1, delimiter: "."}
+	if continued {
+		item = listItem{number: prevItem.number + 1, delimiter: prevItem.delimiter}
+	}
+
+	sequence, err := strconv.Atoi(p.extra(node.Block).orderedSequence())
+	if err != nil || sequence == item.number {
+		return item
+	}
+	item.number = sequence
+	if continued {
+		// Markdown 只使用列表第一项的编号，编号不连续时需要开始新的列表
+		item.delimiter = lo.Ternary(prevItem.delimiter == ".", ")", ".")
+	}
+	return item
+}
+
+// sameList 相邻的两个块是否在同一个 Markdown 列表中
+func sameList(prev, node *Node, prevItem, item listItem) bool {
+	if !isListItem(prev) || !isListItem(node) {
+		return false
+	}
+	prevOrdered, ordered := lo.FromPtr(prev.BlockType) == Ordered, lo.FromPtr(node.BlockType) == Ordered
+	if prevOrdered != ordered {
+		return false
+	}
+	return !ordered || prevItem.delimiter == item.delimiter
+}
+
+// tightListItem 列表项是否可以紧凑输出：子块都是可以紧凑输出的列表项
+func tightListItem(node *Node) bool {
+	if !isListItem(node) {
+		return false
+	}
+	for _, child := range node.ChildrenNode {
+		// OKR 进展不输出内容
+		if child != nil && lo.FromPtr(child.BlockType) == OkrProgress {
+			continue
+		}
+		if !tightListItem(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// listIndent 列表项子块的缩进，与列表标记的宽度相同
+func listIndent(ctx context.Context, node *Node) string {
+	if lo.FromPtr(node.BlockType) == Ordered {
+		return strings.Repeat(" ", len(orderedMarker(ctx))+1)
+	}
+	return "  "
+}
+
+// tight 文本与下一个文本之间只换一行
+func tight(text string) string {
+	return text + "\n0x3f3f3f"
+}
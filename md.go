@@ -3,10 +3,9 @@ package lark_docx_md
 import (
 	"context"
 	"fmt"
-	"io"
+	"html"
 	"log"
-	"os"
-	"path/filepath"
+	"path"
 	"strings"
 
 	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
@@ -22,16 +21,22 @@ func (p *DocxMarkdownProcessor) DocxBlockMarkdown(ctx context.Context, root *Nod
 
 	// 先处理子块
 	var subBlockTexts []string
-	for _, childNode := range root.ChildrenNode {
-		subBlockTexts = append(subBlockTexts, p.DocxBlockMarkdown(ctx, childNode)...)
-	}
+	p.childrenMarkdown(ctx, root, func(texts []string) {
+		subBlockTexts = append(subBlockTexts, texts...)
+	})
 
 	// 再处理父块
+	if p.hasAnchor(curBlock) {
+		ctx = withAnchor(ctx, *curBlock.BlockId)
+	}
 	parentText := ""
 	switch *curBlock.BlockType {
 	case Page:
 		parentText = p.BlockPageMarkdown(ctx, curBlock)
 	case Text:
+		if p.toc != "" && p.isTOCMarker(ctx, root) {
+			return []string{p.toc}
+		}
 		parentText = p.BlockTextMarkdown(ctx, curBlock)
 	case Heading1, Heading2, Heading3, Heading4, Heading5, Heading6, Heading7, Heading8, Heading9:
 		parentText = p.BlockHeadingMarkdown(ctx, curBlock)
@@ -51,8 +56,41 @@ func (p *DocxMarkdownProcessor) DocxBlockMarkdown(ctx context.Context, root *Nod
 		parentText = p.BlockDividerMarkdown(ctx)
 	case Image:
 		parentText = p.BlockImageMarkdown(ctx, curBlock)
+	case Diagram:
+		parentText = p.BlockDiagramMarkdown(ctx, curBlock)
+	case Iframe:
+		parentText = p.BlockIframeMarkdown(ctx, curBlock)
+	case LinkPreview:
+		parentText = p.BlockLinkPreviewMarkdown(ctx, curBlock)
+	case AddOns:
+		return p.BlockAddOnsMarkdown(ctx, curBlock)
+	case Board:
+		parentText = p.BlockBoardMarkdown(ctx, curBlock)
+	case Task:
+		parentText = p.BlockTaskMarkdown(ctx, curBlock)
+	case Okr:
+		parentText = p.BlockOkrMarkdown(ctx, curBlock)
+	case OkrObjective:
+		parentText = p.BlockOkrObjectiveMarkdown(ctx, curBlock)
+	case OkrKeyResult:
+		parentText = p.BlockOkrKeyResultMarkdown(ctx, curBlock)
+	case OkrProgress:
+		// 进展已输出在 Objective 和 Key Result 中
+		return nil
+	case ChatCard:
+		if p.config().OmitChatCard {
+			return nil
+		}
+		parentText = p.BlockChatCardMarkdown(ctx, curBlock)
+	case JiraIssue:
+		parentText = p.BlockJiraIssueMarkdown(ctx, curBlock)
+	case SourceSynced:
+		// 源同步块只输出子块
+	case ReferenceSynced:
+		parentText = p.BlockReferenceSyncedMarkdown(ctx, root)
 	case TableCell:
-		return subBlockTexts
+		// 单元格只能在一行中，多个段落使用 <br> 分隔
+		return []string{strings.Join(subBlockTexts, "<br>")}
 	case Table:
 		return p.BlockTableMarkdown(ctx, curBlock, subBlockTexts)
 	case QuoteContainer:
@@ -61,15 +99,27 @@ func (p *DocxMarkdownProcessor) DocxBlockMarkdown(ctx context.Context, root *Nod
 		parentText = fmt.Sprintf("<!-- not support block type %d -->", *curBlock.BlockType)
 	}
 
+	// 折叠的标题和文本块
+	if p.config().FoldedDetails && len(subBlockTexts) > 0 {
+		if text := foldedText(curBlock); text != nil {
+			return p.BlockFoldedMarkdown(ctx, text, subBlockTexts)
+		}
+	}
+
 	// 合并父块和子块
 	var tmp []string
 	if parentText != "" {
+		if !p.config().LooseList && len(subBlockTexts) > 0 && tightListItem(root) {
+			parentText = tight(parentText)
+		}
 		tmp = append(tmp, parentText)
 	}
 	for _, text := range subBlockTexts {
 		switch *curBlock.BlockType {
-		case Page, Heading1, Heading2, Heading3, Heading4, Heading5, Heading6, Heading7, Heading8, Heading9:
+		case Page, Heading1, Heading2, Heading3, Heading4, Heading5, Heading6, Heading7, Heading8, Heading9, Okr, SourceSynced, ReferenceSynced:
 			tmp = append(tmp, text)
+		case Bullet, Ordered, Todo, Task, OkrObjective, OkrKeyResult:
+			tmp = append(tmp, listIndent(ctx, root)+text)
 		default:
 			tmp = append(tmp, "    "+text)
 		}
@@ -77,8 +127,46 @@ func (p *DocxMarkdownProcessor) DocxBlockMarkdown(ctx context.Context, root *Nod
 	return tmp
 }
 
+// childrenMarkdown 依次输出子块，同一列表中相邻的列表项之间只换一行
+// 是否只换一行由下一个子块决定，因此子块的文本在下一个有文本的子块输出后才交给 emit
+func (p *DocxMarkdownProcessor) childrenMarkdown(ctx context.Context, root *Node, emit func(texts []string)) {
+	childCtx := ctx
+	if *root.BlockType == TableCell {
+		childCtx = withEscape(ctx, escapeTableCell)
+	}
+	var (
+		pending  []string
+		prev     *Node
+		prevItem listItem
+	)
+	for _, childNode := range root.ChildrenNode {
+		var item listItem
+		itemCtx := childCtx
+		if lo.FromPtr(childNode.BlockType) == Ordered {
+			item = p.nextListItem(childNode, prev, prevItem)
+			itemCtx = withListItem(childCtx, item)
+		}
+		if !p.config().LooseList && len(pending) > 0 && tightListItem(prev) && sameList(prev, childNode, prevItem, item) {
+			pending[len(pending)-1] = tight(pending[len(pending)-1])
+		}
+		if texts := p.DocxBlockMarkdown(itemCtx, childNode); len(texts) > 0 {
+			if len(pending) > 0 {
+				emit(pending)
+			}
+			pending = texts
+		}
+		prev, prevItem = childNode, item
+	}
+	if len(pending) > 0 {
+		emit(pending)
+	}
+}
+
 func (p *DocxMarkdownProcessor) BlockPageMarkdown(ctx context.Context, block *larkdocx.Block) string {
-	return "# " + p.TextMarkdown(ctx, block.Page)
+	if p.config().TitleFrontMatter {
+		return titleFrontMatter(p.TextMarkdown(ctx, block.Page, true))
+	}
+	return "# " + p.TextMarkdown(withEscape(ctx, escapeHeading), block.Page)
 }
 
 func (p *DocxMarkdownProcessor) BlockTextMarkdown(ctx context.Context, block *larkdocx.Block) string {
@@ -86,6 +174,25 @@ func (p *DocxMarkdownProcessor) BlockTextMarkdown(ctx context.Context, block *la
 }
 
 func (p *DocxMarkdownProcessor) BlockHeadingMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	heading := headingText(block)
+
+	// block type: [3, 11] -> heading: [1, 9] -> markdown [1, 6]
+	level := p.headingLevel(*block.BlockType)
+	switch {
+	case level <= 6:
+	case p.config().DeepHeading == DeepHeadingBold:
+		return p.TextMarkdown(withEscape(ctx, escapeHeading), boldText(heading))
+	case p.config().DeepHeading == DeepHeadingHTML:
+		id := lo.Ternary(anchorOf(ctx) == "", "", fmt.Sprintf(` id="%s"`, anchorOf(ctx)))
+		return fmt.Sprintf(`<h6 data-level="%d"%s>%s</h6>`, level, id, html.EscapeString(strings.ReplaceAll(p.TextMarkdown(ctx, heading, true), "\n", " ")))
+	default:
+		level = 6
+	}
+	return strings.Repeat("#", level) + " " + p.TextMarkdown(withEscape(ctx, escapeHeading), heading)
+}
+
+// headingText 返回标题块的文本
+func headingText(block *larkdocx.Block) *larkdocx.Text {
 	var heading *larkdocx.Text
 	if block.Heading1 != nil {
 		heading = block.Heading1
@@ -106,11 +213,7 @@ func (p *DocxMarkdownProcessor) BlockHeadingMarkdown(ctx context.Context, block
 	} else if block.Heading9 != nil {
 		heading = block.Heading9
 	}
-
-	// block type: [3, 11] -> heading: [1, 9] -> markdown [1, 6]
-	cnt := *block.BlockType - 2
-	cnt = lo.Ternary(cnt > 6, 6, cnt)
-	return strings.Repeat("#", cnt) + " " + p.TextMarkdown(ctx, heading)
+	return heading
 }
 
 func (p *DocxMarkdownProcessor) BlockBulletMarkdown(ctx context.Context, block *larkdocx.Block) string {
@@ -118,16 +221,55 @@ func (p *DocxMarkdownProcessor) BlockBulletMarkdown(ctx context.Context, block *
 }
 
 func (p *DocxMarkdownProcessor) BlockOrderedMarkdown(ctx context.Context, block *larkdocx.Block) string {
-	return "1. " + p.TextMarkdown(ctx, block.Ordered)
+	return orderedMarker(ctx) + " " + p.TextMarkdown(ctx, block.Ordered)
 }
 
 func (p *DocxMarkdownProcessor) BlockCodeMarkdown(ctx context.Context, block *larkdocx.Block) (texts []string) {
-	texts = append(texts, fmt.Sprintf("```%s", languageMap[*block.Code.Style.Language]))
-	texts = append(texts, strings.Split(p.TextMarkdown(ctx, block.Code, true), "\n")...)
-	texts = append(texts, "```")
+	code := p.TextMarkdown(ctx, block.Code, true)
+	fence := codeFence(code)
+	texts = append(texts, fence+p.codeInfo(block))
+	texts = append(texts, strings.Split(code, "\n")...)
+	texts = append(texts, fence)
 	return FixTexts(texts)
 }
 
+// codeInfo 返回代码块的信息字符串：语言，以及 CodeTitle 时的标题属性，eg. go title="main.go"
+func (p *DocxMarkdownProcessor) codeInfo(block *larkdocx.Block) string {
+	language := p.config().CodeLanguage
+	if block.Code != nil && block.Code.Style != nil {
+		if l, ok := languageMap[lo.FromPtr(block.Code.Style.Language)]; ok {
+			language = l
+		}
+	}
+	if !p.config().CodeTitle {
+		return language
+	}
+	title := strings.Join(strings.Fields(p.extra(block).codeCaption()), " ")
+	// 标题属性不支持转义，同时包含两种引号时无法引用；反引号围栏的信息字符串中不能有反引号，都不输出标题
+	if title == "" || (strings.Contains(title, `"`) && strings.Contains(title, "'")) || strings.Contains(title, "`") {
+		return language
+	}
+	// 信息字符串的第一个词是语言，有标题时不能省略
+	language = lo.Ternary(language == "", languageMap[PlainText], language)
+	if strings.Contains(title, `"`) {
+		return fmt.Sprintf("%s title='%s'", language, title)
+	}
+	return fmt.Sprintf("%s title=\"%s\"", language, title)
+}
+
+// codeFence 返回代码块的围栏，反引号的数量多于代码中行首最长的连续反引号
+func codeFence(code string) string {
+	longest := 2
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimLeft(line, " ")
+		n := len(line) - len(strings.TrimLeft(line, "`"))
+		if n > longest {
+			longest = n
+		}
+	}
+	return strings.Repeat("`", longest+1)
+}
+
 func (p *DocxMarkdownProcessor) BlockQuoteMarkdown(ctx context.Context, block *larkdocx.Block) string {
 	return "> " + p.TextMarkdown(ctx, block.Quote)
 }
@@ -171,91 +313,97 @@ func (p *DocxMarkdownProcessor) TextMarkdown(ctx context.Context, text *larkdocx
 	}
 
 	buf := new(strings.Builder)
-
-	preStyle := larkdocx.NewTextElementStyleBuilder().Bold(false).InlineCode(false).Italic(false).Strikethrough(false).Underline(false).Build()
-	for _, e := range text.Elements {
-		// 将链接和@文档都转成普通文字处理
-		textRun := e.TextRun
-		if textRun != nil && textRun.TextElementStyle.Link != nil {
-			textRun = larkdocx.NewTextRunBuilder().
-				Content(fmt.Sprintf("[%s](%s)", *textRun.Content, UnescapeUrl(*textRun.TextElementStyle.Link.Url))).
-				TextElementStyle(textRun.TextElementStyle).
-				Build()
-		}
-		if e.MentionDoc != nil {
-			textRun = larkdocx.NewTextRunBuilder().
-				Content(fmt.Sprintf("[%s](%s)", *e.MentionDoc.Title, UnescapeUrl(*e.MentionDoc.Url))).
-				TextElementStyle(e.MentionDoc.TextElementStyle).
-				Build()
-		}
-		// 处理文本
-		if textRun != nil {
-			// 相邻文本样式相同则统一加样式，不同则开启新样式
-			if !withoutStyle &&
-				(*textRun.TextElementStyle.Bold != *preStyle.Bold ||
-					*textRun.TextElementStyle.InlineCode != *preStyle.InlineCode ||
-					*textRun.TextElementStyle.Italic != *preStyle.Italic ||
-					*textRun.TextElementStyle.Strikethrough != *preStyle.Strikethrough ||
-					*textRun.TextElementStyle.Underline != *preStyle.Underline) {
-				// 结束上一个样式
-				if *preStyle.Bold {
-					buf.WriteString("**")
-				}
-				if *preStyle.InlineCode {
-					buf.WriteString("`")
-				}
-				if *preStyle.Italic {
-					buf.WriteString("*")
-				}
-				if *preStyle.Strikethrough {
-					buf.WriteString("~~")
-				}
-				if *preStyle.Underline {
-					buf.WriteString("</u>")
-				}
-				// 开启下一个样式
-				if *textRun.TextElementStyle.Bold {
-					buf.WriteString("**")
-				}
-				if *textRun.TextElementStyle.InlineCode {
-					buf.WriteString("`")
-				}
-				if *textRun.TextElementStyle.Italic {
-					buf.WriteString("*")
-				}
-				if *textRun.TextElementStyle.Strikethrough {
-					buf.WriteString("~~")
-				}
-				if *textRun.TextElementStyle.Underline {
-					buf.WriteString("<u>")
-				}
+	if withoutStyle {
+		// 不加样式时原样输出文本，eg. 代码块
+		for _, e := range text.Elements {
+			if e.TextRun != nil {
+				buf.WriteString(lo.FromPtr(e.TextRun.Content))
+			}
+			if e.MentionDoc != nil {
+				buf.WriteString(lo.FromPtr(e.MentionDoc.Title))
 			}
-			buf.WriteString(*textRun.Content)
-			preStyle = textRun.TextElementStyle
 		}
-	}
-	if withoutStyle {
 		return buf.String()
 	}
 
-	// 最后一个文本的结束样式
-	if *preStyle.Bold {
-		buf.WriteString("**")
+	// 相邻文本样式相同则合并，统一加样式
+	var segments []*textSegment
+	if blockId := anchorOf(ctx); blockId != "" {
+		segments = append(segments, &textSegment{style: normalizeStyle(nil)})
+		segments[0].content.WriteString(anchorMarkdown(blockId))
 	}
-	if *preStyle.InlineCode {
-		buf.WriteString("`")
+	lineStart := true
+	for i, e := range text.Elements {
+		content, style, ok := p.elementMarkdown(ctx, e, lineStart)
+		if !ok {
+			continue
+		}
+		colorOpen, colorClose := p.colorMarkup(style)
+		if n := len(segments); n == 0 || !segments[n-1].sameStyle(style, colorOpen) {
+			segments = append(segments, &textSegment{style: style, colorOpen: colorOpen, colorClose: colorClose})
+		}
+		segments[len(segments)-1].content.WriteString(content)
+		lineStart = strings.HasSuffix(content, "\n")
+
+		// 评论的脚注引用不加样式
+		if refs := p.commentRefs(style, text.Elements[i+1:]); refs != "" {
+			segments = append(segments, &textSegment{style: normalizeStyle(nil)})
+			segments[len(segments)-1].content.WriteString(refs)
+			lineStart = false
+		}
 	}
-	if *preStyle.Italic {
-		buf.WriteString("*")
+
+	return renderSegments(ctx, segments)
+}
+
+// elementMarkdown 输出文本元素转义后的内容及其样式，行内代码的内容不转义
+// 链接和@文档都转成普通文字处理，不支持的元素返回 false
+func (p *DocxMarkdownProcessor) elementMarkdown(ctx context.Context, e *larkdocx.TextElement, lineStart bool) (string, *larkdocx.TextElementStyle, bool) {
+	switch {
+	case e.TextRun != nil:
+		content := lo.FromPtr(e.TextRun.Content)
+		style := p.colorStyle(normalizeStyle(e.TextRun.TextElementStyle))
+		if style.Link == nil {
+			if *style.InlineCode {
+				return content, style, true
+			}
+			return escapeText(ctx, content, lineStart), style, true
+		}
+		link := p.internalLink(UnescapeUrl(lo.FromPtr(style.Link.Url)))
+		if *style.InlineCode {
+			// 行内代码链接输出为链接包裹代码
+			code := *style
+			code.InlineCode = lo.ToPtr(false)
+			return fmt.Sprintf("[%s](%s)", codeSpan(ctx, content), escapeURL(link)), &code, true
+		}
+		return fmt.Sprintf("[%s](%s)", escapeText(withEscape(ctx, escapeLinkText), content, false), escapeURL(link)), style, true
+	case e.MentionDoc != nil:
+		style := p.colorStyle(normalizeStyle(e.MentionDoc.TextElementStyle))
+		title := escapeText(withEscape(ctx, escapeLinkText), lo.FromPtr(e.MentionDoc.Title), false)
+		return fmt.Sprintf("[%s](%s)", title, escapeURL(p.internalLink(UnescapeUrl(lo.FromPtr(e.MentionDoc.Url))))), style, true
+	case e.Reminder != nil:
+		style := p.colorStyle(normalizeStyle(e.Reminder.TextElementStyle))
+		return p.reminderMarkdown(ctx, e.Reminder), style, true
+	case e.File != nil:
+		style := p.colorStyle(normalizeStyle(e.File.TextElementStyle))
+		return p.inlineFileMarkdown(ctx, e.File), style, true
+	default:
+		return "", nil, false
 	}
-	if *preStyle.Strikethrough {
-		buf.WriteString("~~")
+}
+
+// normalizeStyle 补全文本样式中未设置的字段
+func normalizeStyle(style *larkdocx.TextElementStyle) *larkdocx.TextElementStyle {
+	normalized := larkdocx.TextElementStyle{}
+	if style != nil {
+		normalized = *style
 	}
-	if *preStyle.Underline {
-		buf.WriteString("</u>")
+	for _, b := range []**bool{&normalized.Bold, &normalized.InlineCode, &normalized.Italic, &normalized.Strikethrough, &normalized.Underline} {
+		if *b == nil {
+			*b = lo.ToPtr(false)
+		}
 	}
-
-	return buf.String()
+	return &normalized
 }
 
 func (p *DocxMarkdownProcessor) BlockDividerMarkdown(ctx context.Context) string {
@@ -263,54 +411,116 @@ func (p *DocxMarkdownProcessor) BlockDividerMarkdown(ctx context.Context) string
 }
 
 func (p *DocxMarkdownProcessor) BlockImageMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	alt, url := p.staticURL(ctx, *block.Image.Token, *block.Image.Token+".jpg")
+	if url == "" {
+		return ""
+	}
+	if p.ImageCaption {
+		if caption := p.extra(block).imageCaption(); caption != "" {
+			alt = caption
+		}
+	}
+	return p.imageMarkdown(ctx, alt, url, block.Image.Width, block.Image.Height, block.Image.Align)
+}
+
+// staticURL 按照静态文件配置获取文件在 Markdown 中的地址：直接使用临时下载地址，或下载到静态文件仓库
+// 返回文件默认的替代文本和地址，失败时地址为空
+func (p *DocxMarkdownProcessor) staticURL(ctx context.Context, token, name string) (string, string) {
+	if p.staticOffline() {
+		// 离线转换或不获取静态文件，使用占位符
+		return token, p.staticPlaceholder(token, name)
+	}
 	if p.StaticAsURL {
 		// 创建请求对象
 		req := larkdrive.NewBatchGetTmpDownloadUrlMediaReqBuilder().
-			FileTokens([]string{*block.Image.Token}).
+			FileTokens([]string{token}).
 			Build()
 		// 发起请求
 		resp, err := p.LarkClient.Drive.V1.Media.BatchGetTmpDownloadUrl(ctx, req)
 		if err != nil {
-			log.Printf("lark get drive media tmp url %s fail: %s", *block.Image.Token, err)
-			return ""
+			log.Printf("lark get drive media tmp url %s fail: %s", token, err)
+			return "", ""
 		}
 		if !resp.Success() {
-			log.Printf("lark get drive media tmp url %s fail: code:%d, msg:%s, requestId:%s", *block.Image.Token, resp.Code, resp.Msg, resp.RequestId())
-			return ""
+			log.Printf("lark get drive media tmp url %s fail: code:%d, msg:%s, requestId:%s", token, resp.Code, resp.Msg, resp.RequestId())
+			return "", ""
 		}
 		for _, v := range resp.Data.TmpDownloadUrls {
-			// return fmt.Sprintf("<img src=%q width=\"%d\" height=\"%d\"/>", *v.TmpDownloadUrl, *block.Image.Width, *block.Image.Height)
-			return fmt.Sprintf("![%s](%s)", *v.FileToken, *v.TmpDownloadUrl)
+			return *v.FileToken, *v.TmpDownloadUrl
 		}
-		return ""
+		return "", ""
 	} else {
-		req := larkdrive.NewDownloadMediaReqBuilder().
-			FileToken(*block.Image.Token).
-			Build()
-		resp, err := p.LarkClient.Drive.Media.Download(ctx, req)
-		if err != nil {
-			log.Printf("lark download drive media %s fail: %s", *block.Image.Token, err)
-			return ""
+		mdname, ok := p.assetStore().Lookup(ctx, token)
+		if !ok {
+			req := larkdrive.NewDownloadMediaReqBuilder().
+				FileToken(token).
+				Build()
+			resp, err := p.LarkClient.Drive.Media.Download(ctx, req)
+			if err != nil {
+				log.Printf("lark download drive media %s fail: %s", token, err)
+				return "", ""
+			}
+			if !resp.Success() {
+				log.Printf("lark download drive media %s fail: code:%d, msg:%s, requestId:%s", token, resp.Code, resp.Msg, resp.RequestId())
+				return "", ""
+			}
+			mdname, err = p.assetStore().Save(ctx, token, name, resp.File)
+			if err != nil {
+				log.Printf("save drive media %s fail: %s", token, err)
+				return "", ""
+			}
 		}
-		if !resp.Success() {
-			log.Printf("lark download drive media %s fail: code:%d, msg:%s, requestId:%s", *block.Image.Token, resp.Code, resp.Msg, resp.RequestId())
-			return ""
+		p.assetTokens = append(p.assetTokens, token)
+		return path.Base(mdname), mdname
+	}
+}
+
+// staticPlaceholder 返回静态文件的占位地址
+func (p *DocxMarkdownProcessor) staticPlaceholder(token, name string) string {
+	placeholder := lo.Ternary(p.StaticPlaceholder == "", "{token}", p.StaticPlaceholder)
+	return strings.NewReplacer("{token}", token, "{name}", name).Replace(placeholder)
+}
+
+// imageMarkdown 按照 ImageSize 配置输出图片及其宽高和对齐方式
+func (p *DocxMarkdownProcessor) imageMarkdown(ctx context.Context, alt, url string, width, height, align *int) string {
+	// Markdown 中的替代文本转义 Markdown 语法，换行会打断图片语法，转为空格
+	mdAlt := escapeText(withEscape(ctx, escapeLinkText), strings.ReplaceAll(alt, "\n", " "), false)
+	switch p.ImageSize {
+	case ImageSizeHTML:
+		attrs := fmt.Sprintf("src=\"%s\" alt=\"%s\"", html.EscapeString(url), html.EscapeString(alt))
+		if lo.FromPtr(width) > 0 {
+			attrs += fmt.Sprintf(" width=\"%d\"", *width)
 		}
-		name := *block.Image.Token + ".jpg"
-		filename := fmt.Sprintf("%s/%s", p.StaticDir, name)
-		mdname := fmt.Sprintf("%s/%s", p.FilePrefix, name)
-		_ = os.MkdirAll(filepath.Dir(filename), 0o755)
-		f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0o666)
-		if err != nil {
-			log.Printf("open file %s fail: %s", filename, err)
-			return ""
+		if lo.FromPtr(height) > 0 {
+			attrs += fmt.Sprintf(" height=\"%d\"", *height)
+		}
+		img := fmt.Sprintf("<img %s/>", attrs)
+		switch lo.FromPtr(align) {
+		case AlignMid:
+			return fmt.Sprintf("<p align=\"center\">%s</p>", img)
+		case AlignRight:
+			return fmt.Sprintf("<p align=\"right\">%s</p>", img)
+		}
+		return img
+	case ImageSizePandoc:
+		var attrs []string
+		if lo.FromPtr(width) > 0 {
+			attrs = append(attrs, fmt.Sprintf("width=%dpx", *width))
+		}
+		if lo.FromPtr(height) > 0 {
+			attrs = append(attrs, fmt.Sprintf("height=%dpx", *height))
+		}
+		switch lo.FromPtr(align) {
+		case AlignMid:
+			attrs = append(attrs, "fig-align=\"center\"")
+		case AlignRight:
+			attrs = append(attrs, "fig-align=\"right\"")
+		}
+		if len(attrs) > 0 {
+			return fmt.Sprintf("![%s](%s){%s}", mdAlt, url, strings.Join(attrs, " "))
 		}
-		defer f.Close()
-
-		_, _ = io.Copy(f, resp.File)
-		// return fmt.Sprintf("<img src=%q width=\"%d\" height=\"%d\"/>", mdname, *block.Image.Width, *block.Image.Height)
-		return fmt.Sprintf("![%s](%s)", name, mdname)
 	}
+	return fmt.Sprintf("![%s](%s)", mdAlt, url)
 }
 
 func (p *DocxMarkdownProcessor) BlockTableMarkdown(ctx context.Context, block *larkdocx.Block, subBlockTexts []string) (texts []string) {
@@ -2,8 +2,6 @@ package lark_docx_md
 
 import (
 	"context"
-	"io"
-	"os"
 	"testing"
 
 	"github.com/bytedance/mockey"
@@ -867,6 +865,7 @@ func TestDocxMarkdownProcessor_BlockCodeMarkdown(t *testing.T) {
 		Config     *Config
 		LarkClient *lark.Client
 		DocumentId string
+		extras     map[string]*blockExtra
 	}
 	type args struct {
 		ctx   context.Context
@@ -909,6 +908,180 @@ func TestDocxMarkdownProcessor_BlockCodeMarkdown(t *testing.T) {
 			},
 			[]string{"```go\n0x3f3f3f", "fmt.Println(\"hello world\")\n0x3f3f3f", "```\n0x3f3f3f\n"},
 		},
+		{
+			"code without style",
+			fields{},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"x := 1",
+									).Build(),
+								).Build(),
+							},
+						).Build(),
+					).Build(),
+			},
+			[]string{"```\n0x3f3f3f", "x := 1\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"code fallback language",
+			fields{Config: &Config{CodeLanguage: "text"}},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"x := 1",
+									).Build(),
+								).Build(),
+							},
+						).Style(larkdocx.NewTextStyleBuilder().Build()).Build(),
+					).Build(),
+			},
+			[]string{"```text\n0x3f3f3f", "x := 1\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"code title",
+			fields{
+				Config: &Config{CodeTitle: true},
+				extras: map[string]*blockExtra{
+					"code": {Code: &codeExtra{Caption: &captionExtra{Content: lo.ToPtr("main.go")}}},
+				},
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"x := 1",
+									).Build(),
+								).Build(),
+							},
+						).Style(larkdocx.NewTextStyleBuilder().Language(Go).Build()).Build(),
+					).Build(),
+			},
+			[]string{"```go title=\"main.go\"\n0x3f3f3f", "x := 1\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"code title with double quotes",
+			fields{
+				Config: &Config{CodeTitle: true},
+				extras: map[string]*blockExtra{
+					"code": {Code: &codeExtra{Caption: &captionExtra{Content: lo.ToPtr(`say "hi".go`)}}},
+				},
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"x := 1",
+									).Build(),
+								).Build(),
+							},
+						).Style(larkdocx.NewTextStyleBuilder().Language(Go).Build()).Build(),
+					).Build(),
+			},
+			[]string{"```go title='say \"hi\".go'\n0x3f3f3f", "x := 1\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"code title with both quotes",
+			fields{
+				Config: &Config{CodeTitle: true},
+				extras: map[string]*blockExtra{
+					"code": {Code: &codeExtra{Caption: &captionExtra{Content: lo.ToPtr(`it's "main".go`)}}},
+				},
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"x := 1",
+									).Build(),
+								).Build(),
+							},
+						).Style(larkdocx.NewTextStyleBuilder().Language(Go).Build()).Build(),
+					).Build(),
+			},
+			[]string{"```go\n0x3f3f3f", "x := 1\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"code title with backtick",
+			fields{
+				Config: &Config{CodeTitle: true},
+				extras: map[string]*blockExtra{
+					"code": {Code: &codeExtra{Caption: &captionExtra{Content: lo.ToPtr("`main`.go")}}},
+				},
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"x := 1",
+									).Build(),
+								).Build(),
+							},
+						).Style(larkdocx.NewTextStyleBuilder().Language(Go).Build()).Build(),
+					).Build(),
+			},
+			[]string{"```go\n0x3f3f3f", "x := 1\n0x3f3f3f", "```\n0x3f3f3f\n"},
+		},
+		{
+			"code contains fence",
+			fields{},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("code").
+					BlockType(Code).
+					Code(
+						larkdocx.NewTextBuilder().Elements(
+							[]*larkdocx.TextElement{
+								larkdocx.NewTextElementBuilder().TextRun(
+									larkdocx.NewTextRunBuilder().Content(
+										"```go\n```",
+									).Build(),
+								).Build(),
+							},
+						).Build(),
+					).Build(),
+			},
+			[]string{"````\n0x3f3f3f", "```go\n0x3f3f3f", "```\n0x3f3f3f", "````\n0x3f3f3f\n"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -916,6 +1089,7 @@ func TestDocxMarkdownProcessor_BlockCodeMarkdown(t *testing.T) {
 				Config:     tt.fields.Config,
 				LarkClient: tt.fields.LarkClient,
 				DocumentId: tt.fields.DocumentId,
+				extras:     tt.fields.extras,
 			}
 			got := p.BlockCodeMarkdown(tt.args.ctx, tt.args.block)
 			assert.Equal(t, tt.want, got)
@@ -967,6 +1141,7 @@ func TestDocxMarkdownProcessor_BlockImageMarkdown(t *testing.T) {
 		Config     *Config
 		LarkClient *lark.Client
 		DocumentId string
+		extras     map[string]*blockExtra
 	}
 	type args struct {
 		ctx   context.Context
@@ -1016,6 +1191,125 @@ func TestDocxMarkdownProcessor_BlockImageMarkdown(t *testing.T) {
 				).Build()
 			},
 		},
+		{
+			"image html size",
+			fields{
+				Config: &Config{
+					StaticAsURL: true,
+					ImageSize:   ImageSizeHTML,
+				},
+				LarkClient: client,
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockType(Image).
+					Image(
+						larkdocx.NewImageBuilder().
+							Token("image-token").
+							Width(100).
+							Height(50).
+							Align(AlignMid).
+							Build(),
+					).Build(),
+			},
+			"<p align=\"center\"><img src=\"image-token-url\" alt=\"image-token\" width=\"100\" height=\"50\"/></p>",
+			func() {
+				mockey.Mock(mockey.GetMethod(client.Drive.V1.Media, "BatchGetTmpDownloadUrl")).Return(
+					&larkdrive.BatchGetTmpDownloadUrlMediaResp{
+						Data: &larkdrive.BatchGetTmpDownloadUrlMediaRespData{
+							TmpDownloadUrls: []*larkdrive.TmpDownloadUrl{
+								{
+									FileToken:      lo.ToPtr("image-token"),
+									TmpDownloadUrl: lo.ToPtr("image-token-url"),
+								},
+							},
+						},
+					},
+					nil,
+				).Build()
+			},
+		},
+		{
+			"image pandoc size with caption",
+			fields{
+				Config: &Config{
+					StaticAsURL:  true,
+					ImageSize:    ImageSizePandoc,
+					ImageCaption: true,
+				},
+				LarkClient: client,
+				extras:     parseBlockExtras([]byte(`{"data":{"items":[{"block_id":"image","image":{"caption":{"content":"架构图"}}}]}}`)),
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("image").
+					BlockType(Image).
+					Image(
+						larkdocx.NewImageBuilder().
+							Token("image-token").
+							Width(100).
+							Height(50).
+							Align(AlignLeft).
+							Build(),
+					).Build(),
+			},
+			"![架构图](image-token-url){width=100px height=50px}",
+			func() {
+				mockey.Mock(mockey.GetMethod(client.Drive.V1.Media, "BatchGetTmpDownloadUrl")).Return(
+					&larkdrive.BatchGetTmpDownloadUrlMediaResp{
+						Data: &larkdrive.BatchGetTmpDownloadUrlMediaRespData{
+							TmpDownloadUrls: []*larkdrive.TmpDownloadUrl{
+								{
+									FileToken:      lo.ToPtr("image-token"),
+									TmpDownloadUrl: lo.ToPtr("image-token-url"),
+								},
+							},
+						},
+					},
+					nil,
+				).Build()
+			},
+		},
+		{
+			"image caption with markdown syntax",
+			fields{
+				Config: &Config{
+					StaticAsURL:  true,
+					ImageCaption: true,
+				},
+				LarkClient: client,
+				extras:     parseBlockExtras([]byte(`{"data":{"items":[{"block_id":"image","image":{"caption":{"content":"图 [1] *架构*\n说明"}}}]}}`)),
+			},
+			args{
+				context.Background(),
+				larkdocx.NewBlockBuilder().
+					BlockId("image").
+					BlockType(Image).
+					Image(
+						larkdocx.NewImageBuilder().
+							Token("image-token").
+							Build(),
+					).Build(),
+			},
+			`![图 \[1\] \*架构\* 说明](image-token-url)`,
+			func() {
+				mockey.Mock(mockey.GetMethod(client.Drive.V1.Media, "BatchGetTmpDownloadUrl")).Return(
+					&larkdrive.BatchGetTmpDownloadUrlMediaResp{
+						Data: &larkdrive.BatchGetTmpDownloadUrlMediaRespData{
+							TmpDownloadUrls: []*larkdrive.TmpDownloadUrl{
+								{
+									FileToken:      lo.ToPtr("image-token"),
+									TmpDownloadUrl: lo.ToPtr("image-token-url"),
+								},
+							},
+						},
+					},
+					nil,
+				).Build()
+			},
+		},
 		{
 			"image download static",
 			fields{
@@ -1044,13 +1338,9 @@ func TestDocxMarkdownProcessor_BlockImageMarkdown(t *testing.T) {
 					&larkdrive.DownloadMediaResp{},
 					nil,
 				).Build()
-				mockey.Mock(os.MkdirAll).Return(nil).Build()
-				mockey.Mock(os.OpenFile).Return(
-					&os.File{},
-					nil,
-				).Build()
-				mockey.Mock(io.Copy).Return(
-					int64(1),
+				mockey.Mock((*AssetStore).Lookup).Return("", false).Build()
+				mockey.Mock((*AssetStore).Save).Return(
+					"static/image-token.jpg",
 					nil,
 				).Build()
 			},
@@ -1062,6 +1352,7 @@ func TestDocxMarkdownProcessor_BlockImageMarkdown(t *testing.T) {
 				Config:     tt.fields.Config,
 				LarkClient: tt.fields.LarkClient,
 				DocumentId: tt.fields.DocumentId,
+				extras:     tt.fields.extras,
 			}
 			mockey.PatchConvey(tt.name, t, func() {
 				tt.mock()
@@ -1317,3 +1608,64 @@ func TestDocxMarkdownProcessor_BlockTodoMarkdown(t *testing.T) {
 		})
 	}
 }
+
+func TestDocxMarkdownProcessor_TextMarkdownColor(t *testing.T) {
+	textRun := func(content string, color, background int) *larkdocx.TextElement {
+		style := larkdocx.NewTextElementStyleBuilder().
+			Bold(false).
+			InlineCode(false).
+			Italic(false).
+			Strikethrough(false).
+			Underline(false)
+		if color != 0 {
+			style.TextColor(color)
+		}
+		if background != 0 {
+			style.BackgroundColor(background)
+		}
+		return larkdocx.NewTextElementBuilder().TextRun(
+			larkdocx.NewTextRunBuilder().Content(content).TextElementStyle(style.Build()).Build(),
+		).Build()
+	}
+	text := larkdocx.NewTextBuilder().Elements(
+		[]*larkdocx.TextElement{
+			textRun("接口 ", 0, 0),
+			textRun("DEPRECATED", TextColorRed, 0),
+			textRun("，请使用 ", 0, 0),
+			textRun("v2", TextColorRed, LightYellow),
+		},
+	).Build()
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   string
+	}{
+		{
+			"ignore color",
+			&Config{},
+			"接口 DEPRECATED，请使用 v2",
+		},
+		{
+			"html",
+			&Config{ColorStyle: ColorStyleHTML},
+			`接口 <span style="color:#D83931">DEPRECATED</span>，请使用 <span style="color:#D83931;background-color:#F8E6AB">v2</span>`,
+		},
+		{
+			"highlight background only",
+			&Config{ColorStyle: ColorStyleHighlight, ColorPalette: &ColorPalette{BackgroundColors: map[int]string{LightYellow: "yellow"}}},
+			"接口 DEPRECATED，请使用 ==v2==",
+		},
+		{
+			"bold red",
+			&Config{ColorStyle: ColorStyleBold, ColorPalette: &ColorPalette{TextColors: map[int]string{TextColorRed: "red"}}},
+			"接口 **DEPRECATED**，请使用 **v2**",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &DocxMarkdownProcessor{Config: tt.config}
+			assert.Equal(t, tt.want, p.TextMarkdown(context.Background(), text))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package lark_docx_md
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// ConvertJSON 将导出的块 JSON 转为 Markdown，不需要 lark 客户端，静态文件输出为占位符
+// 支持以下格式：
+//   - 块列表：[{"block_id": "...", "block_type": 1, ...}, ...]
+//   - 获取文档所有块接口的响应：{"code": 0, "data": {"items": [...]}}
+//   - 文档及其块列表：{"document": {"document_id": "...", ...}, "blocks": [...]}
+func ConvertJSON(data []byte, opts ...Option) (string, error) {
+	p := NewDocxMarkdownProcessor(nil, Docx, "", opts...)
+	return p.ConvertJSON(context.Background(), data)
+}
+
+// ConvertJSON 将导出的块 JSON 转为 Markdown，格式见 ConvertJSON
+func (p *DocxMarkdownProcessor) ConvertJSON(ctx context.Context, data []byte) (string, error) {
+	documentId, items, err := parseBlocksJSON(data)
+	if err != nil {
+		return "", err
+	}
+
+	var blocks []*larkdocx.Block
+	if err := json.Unmarshal(items, &blocks); err != nil {
+		return "", err
+	}
+	var extras []*blockExtra
+	if err := json.Unmarshal(items, &extras); err != nil {
+		return "", err
+	}
+	p.extras = make(map[string]*blockExtra, len(extras))
+	for _, extra := range extras {
+		if extra != nil && extra.BlockId != nil {
+			p.extras[*extra.BlockId] = extra
+		}
+	}
+	if documentId != "" {
+		p.DocumentId = documentId
+	}
+	return p.ConvertBlocks(ctx, blocks)
+}
+
+// parseBlocksJSON 识别块 JSON 的格式，返回文档 token 和块列表的 JSON
+func parseBlocksJSON(data []byte) (string, json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		return "", data, nil
+	}
+
+	var v struct {
+		Document *larkdocx.Document `json:"document"`
+		Blocks   json.RawMessage    `json:"blocks"`
+		Data     *struct {
+			Items json.RawMessage `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", nil, err
+	}
+	switch {
+	case v.Blocks != nil:
+		documentId := ""
+		if v.Document != nil {
+			documentId = lo.FromPtr(v.Document.DocumentId)
+		}
+		return documentId, v.Blocks, nil
+	case v.Data != nil && v.Data.Items != nil:
+		return "", v.Data.Items, nil
+	default:
+		return "", nil, errors.New("unrecognized blocks json, want a block list, a list blocks response or a document with blocks")
+	}
+}
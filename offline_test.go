@@ -0,0 +1,39 @@
+package lark_docx_md
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertJSON(t *testing.T) {
+	const blocks = `[
+		{"block_id": "doxcnRoot", "block_type": 1, "children": ["doxcnImage"], "page": {"elements": [{"text_run": {"content": "Offline", "text_element_style": {"bold": false, "inline_code": false, "italic": false, "strikethrough": false, "underline": false}}}]}},
+		{"block_id": "doxcnImage", "block_type": 27, "parent_id": "doxcnRoot", "image": {"token": "boxcnImage", "width": 100, "height": 50, "caption": {"content": "架构图"}}}
+	]`
+	const want = "# Offline\n\n![架构图](https://example.com/static/boxcnImage.jpg)" +
+		"\n\n***\n_This MARKDOWN was generated with ❤️ by [lark_docx_md](https://github.com/A11Might/lark_docx_md)_"
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"block list", blocks, false},
+		{"list blocks response", `{"code": 0, "msg": "success", "data": {"has_more": false, "items": ` + blocks + `}}`, false},
+		{"document with blocks", `{"document": {"document_id": "doxcnRoot", "revision_id": 3}, "blocks": ` + blocks + `}`, false},
+		{"unrecognized", `{"items": []}`, true},
+		{"no block", `[]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertJSON([]byte(tt.data), UseImageCaption(), UseStaticPlaceholder("https://example.com/static/{name}"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
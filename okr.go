@@ -0,0 +1,66 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// BlockOkrMarkdown OKR 输出为加粗的周期名，其下的 Objective 和 Key Result 输出为嵌套列表
+func (p *DocxMarkdownProcessor) BlockOkrMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	var period string
+	if block.Okr != nil {
+		period = lo.Ternary(lo.FromPtr(block.Okr.PeriodNameZh) != "", lo.FromPtr(block.Okr.PeriodNameZh), lo.FromPtr(block.Okr.PeriodNameEn))
+	}
+	if period == "" {
+		return "**OKR**"
+	}
+	return fmt.Sprintf("**OKR %s**", escapeText(ctx, period, false))
+}
+
+// BlockOkrObjectiveMarkdown Objective 输出为列表项，eg. - **O1** 内容 (50%)
+func (p *DocxMarkdownProcessor) BlockOkrObjectiveMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	objective := block.OkrObjective
+	if objective == nil {
+		return "<!-- empty okr objective -->"
+	}
+	return okrItemMarkdown(fmt.Sprintf("O%d", lo.FromPtr(objective.Position)), p.TextMarkdown(ctx, objective.Content), objective.ProgressRate)
+}
+
+// BlockOkrKeyResultMarkdown Key Result 输出为列表项，eg. - **KR1** 内容 (30%)
+func (p *DocxMarkdownProcessor) BlockOkrKeyResultMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	kr := block.OkrKeyResult
+	if kr == nil {
+		return "<!-- empty okr key result -->"
+	}
+	return okrItemMarkdown(fmt.Sprintf("KR%d", lo.FromPtr(kr.Position)), p.TextMarkdown(ctx, kr.Content), kr.ProgressRate)
+}
+
+func okrItemMarkdown(label, content string, progress *larkdocx.OkrProgressRate) string {
+	text := fmt.Sprintf("- **%s** %s", label, content)
+	if rate := okrProgress(progress); rate != "" {
+		text += fmt.Sprintf(" (%s)", rate)
+	}
+	return text
+}
+
+// okrProgress 返回进展：simple 模式为百分比，advanced 模式为当前值和目标值
+func okrProgress(progress *larkdocx.OkrProgressRate) string {
+	if progress == nil {
+		return ""
+	}
+	if lo.FromPtr(progress.Mode) == "advanced" && progress.Target != nil {
+		return fmt.Sprintf("%s / %s", formatFloat(lo.FromPtr(progress.Current)), formatFloat(*progress.Target))
+	}
+	if progress.Percent == nil {
+		return ""
+	}
+	return formatFloat(*progress.Percent) + "%"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,22 @@
+package lark_docx_md
+
+import (
+	"context"
+	"testing"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockOkrMarkdown(t *testing.T) {
+	ctx := context.Background()
+	p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage")
+
+	assert.Equal(t, "**OKR 2024 年 1 月 - 6 月**", p.BlockOkrMarkdown(ctx, larkdocx.NewBlockBuilder().BlockType(Okr).
+		Okr(larkdocx.NewOkrBuilder().PeriodNameZh("2024 年 1 月 - 6 月").PeriodNameEn("Jan - Jun 2024").Build()).Build()))
+	assert.Equal(t, "**OKR Jan - Jun 2024**", p.BlockOkrMarkdown(ctx, larkdocx.NewBlockBuilder().BlockType(Okr).
+		Okr(larkdocx.NewOkrBuilder().PeriodNameEn("Jan - Jun 2024").Build()).Build()))
+	assert.Equal(t, "**OKR**", p.BlockOkrMarkdown(ctx, larkdocx.NewBlockBuilder().BlockType(Okr).Build()))
+	assert.Equal(t, "<!-- empty okr objective -->", p.BlockOkrObjectiveMarkdown(ctx, larkdocx.NewBlockBuilder().BlockType(OkrObjective).Build()))
+	assert.Equal(t, "<!-- empty okr key result -->", p.BlockOkrKeyResultMarkdown(ctx, larkdocx.NewBlockBuilder().BlockType(OkrKeyResult).Build()))
+}
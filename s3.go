@@ -0,0 +1,214 @@
+package lark_docx_md
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3StaticStore 兼容 S3 协议的对象存储，如 AWS S3、MinIO、Cloudflare R2 等
+// 使用 path-style 地址（{Endpoint}/{Bucket}/{Key}）上传，请求使用 AWS Signature V4 签名
+type S3StaticStore struct {
+	Endpoint        string       // 对象存储地址，eg. https://s3.us-east-1.amazonaws.com
+	Region          string       // 区域，为空时使用 us-east-1
+	Bucket          string       // 存储桶
+	AccessKeyId     string       // 访问密钥 ID
+	SecretAccessKey string       // 访问密钥
+	KeyPrefix       string       // 对象 key 的前缀，eg. docs/static
+	PublicURL       string       // 文件在 Markdown 中的地址前缀，eg. CDN 域名；为空时使用对象存储地址
+	HTTPClient      *http.Client // 为空时使用 http.DefaultClient
+}
+
+func NewS3StaticStore(endpoint, region, bucket, accessKeyId, secretAccessKey string) *S3StaticStore {
+	return &S3StaticStore{
+		Endpoint:        endpoint,
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyId:     accessKeyId,
+		SecretAccessKey: secretAccessKey,
+	}
+}
+
+// Put 上传文件，签名需要文件内容的哈希：r 支持 Seek 时先读一遍计算哈希再上传，否则读入内存
+func (s *S3StaticStore) Put(ctx context.Context, name string, r io.Reader) (string, error) {
+	body, size, payloadHash, err := s3Payload(r)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.key(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if typ := mime.TypeByExtension(path.Ext(name)); typ != "" {
+		req.Header.Set("Content-Type", typ)
+	}
+	if err := s.do(req, payloadHash); err != nil {
+		return "", err
+	}
+
+	if s.PublicURL != "" {
+		return strings.TrimSuffix(s.PublicURL, "/") + "/" + key, nil
+	}
+	return s.objectURL(key), nil
+}
+
+func (s *S3StaticStore) Remove(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(s.key(name)), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, sha256Hex(nil))
+}
+
+// s3Payload 返回上传的内容、大小和 sha256
+func s3Payload(r io.Reader) (io.Reader, int64, string, error) {
+	seeker, ok := r.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		return bytes.NewReader(data), int64(len(data)), sha256Hex(data), nil
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	size, sum, err := hashReader(seeker)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return nil, 0, "", err
+	}
+	return io.NopCloser(seeker), size, sum, nil
+}
+
+func (s *S3StaticStore) key(name string) string {
+	if s.KeyPrefix == "" {
+		return name
+	}
+	return strings.Trim(s.KeyPrefix, "/") + "/" + name
+}
+
+func (s *S3StaticStore) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.Endpoint, "/"), s.Bucket, key)
+}
+
+func (s *S3StaticStore) do(req *http.Request, payloadHash string) error {
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s fail: status:%d, body:%s", req.Method, req.URL.Path, resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// sign 使用 AWS Signature V4 为请求签名
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *S3StaticStore) sign(req *http.Request, payloadHash string, now time.Time) {
+	region := s.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	var headerNames []string
+	for name := range req.Header {
+		headerNames = append(headerNames, strings.ToLower(name))
+	}
+	sort.Strings(headerNames)
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSha256(key, region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyId, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+	return strings.ReplaceAll(strings.Join(pairs, "&"), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,155 @@
+package lark_docx_md
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StaticStore 静态文件存储
+type StaticStore interface {
+	// Put 保存静态文件，返回在 Markdown 中引用该文件的地址
+	Put(ctx context.Context, name string, r io.Reader) (string, error)
+}
+
+// StaticOpener 支持读取已保存文件的存储，AssetStore 用于校验文件是否需要重新下载
+type StaticOpener interface {
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// StaticRemover 支持删除已保存文件的存储，AssetStore 用于清理不再被引用的文件
+type StaticRemover interface {
+	Remove(ctx context.Context, name string) error
+}
+
+// LocalStaticStore 本地文件系统存储
+type LocalStaticStore struct {
+	Dir       string // 静态文件目录
+	URLPrefix string // 文件在 Markdown 中的前缀
+}
+
+func NewLocalStaticStore(dir, urlPrefix string) *LocalStaticStore {
+	return &LocalStaticStore{
+		Dir:       dir,
+		URLPrefix: urlPrefix,
+	}
+}
+
+func (s *LocalStaticStore) Put(ctx context.Context, name string, r io.Reader) (string, error) {
+	filename, err := s.path(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o666)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", s.URLPrefix, name), nil
+}
+
+func (s *LocalStaticStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	filename, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(filename)
+}
+
+func (s *LocalStaticStore) Remove(ctx context.Context, name string) error {
+	filename, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filename)
+}
+
+// path 返回文件在静态文件目录中的路径，拒绝跳出目录的文件名
+func (s *LocalStaticStore) path(name string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid static file name %q", name)
+	}
+	return filepath.Join(s.Dir, rel), nil
+}
+
+// MemoryStaticStore 内存存储，适用于测试或由调用方自行处理文件的场景
+type MemoryStaticStore struct {
+	URLPrefix string // 文件在 Markdown 中的前缀
+
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+func NewMemoryStaticStore(urlPrefix string) *MemoryStaticStore {
+	return &MemoryStaticStore{
+		URLPrefix: urlPrefix,
+		files:     make(map[string][]byte),
+	}
+}
+
+func (s *MemoryStaticStore) Put(ctx context.Context, name string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		s.files = make(map[string][]byte)
+	}
+	s.files[name] = data
+	return fmt.Sprintf("%s/%s", s.URLPrefix, name), nil
+}
+
+func (s *MemoryStaticStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	data, ok := s.Get(name)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MemoryStaticStore) Remove(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[name]; !ok {
+		return os.ErrNotExist
+	}
+	delete(s.files, name)
+	return nil
+}
+
+// Get 读取已保存的文件内容
+func (s *MemoryStaticStore) Get(name string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.files[name]
+	return data, ok
+}
+
+// Names 返回全部已保存的文件名
+func (s *MemoryStaticStore) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var names []string
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,243 @@
+package lark_docx_md
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// SyncDocument 需要同步的文档
+type SyncDocument struct {
+	Typ   string // 文档类型，eg. docx, wiki
+	Token string // 文档 token
+	Path  string // Markdown 文件的输出路径
+}
+
+// SyncResult 同步结果，记录各类文档的 token
+type SyncResult struct {
+	Added     []string // 新增的文档
+	Updated   []string // 版本变化后重新导出的文档
+	Unchanged []string // 版本未变化，跳过导出的文档
+	Deleted   []string // 不再需要同步，已删除输出文件的文档
+}
+
+func (r *SyncResult) String() string {
+	return fmt.Sprintf("added: %d, updated: %d, unchanged: %d, deleted: %d",
+		len(r.Added), len(r.Updated), len(r.Unchanged), len(r.Deleted))
+}
+
+// SyncChange 一个文档同步后产生的变更
+type SyncChange struct {
+	Kind       string   // 变更类型，eg. added, updated, deleted
+	Token      string   // 文档 token
+	DocumentId string   // docx 文档 token
+	RevisionId int      // 导出的文档版本，删除时为 0
+	Paths      []string // 发生变化的文件和目录，包括 Markdown 文件、同步状态文件和本地静态文件目录
+}
+
+// SyncCommitter 在每个文档同步后提交变更，eg. 创建 git 提交
+type SyncCommitter interface {
+	Commit(ctx context.Context, change *SyncChange) error
+}
+
+// Syncer 增量同步文档，只重新导出版本发生变化的文档
+type Syncer struct {
+	LarkClient *lark.Client  // lark 客户端
+	StatePath  string        // 同步状态文件路径，记录每个文档已导出的版本
+	Options    []Option      // 导出文档使用的选项
+	Committer  SyncCommitter // 提交每个文档的变更，为空时不提交
+}
+
+type syncState struct {
+	Documents map[string]*syncEntry `json:"documents"` // 文档 token -> 同步记录
+}
+
+type syncEntry struct {
+	Typ        string `json:"type"`
+	DocumentId string `json:"document_id"`
+	RevisionId int    `json:"revision_id"`
+	Path       string `json:"path"`
+}
+
+func NewSyncer(client *lark.Client, statePath string, opts ...Option) *Syncer {
+	return &Syncer{
+		LarkClient: client,
+		StatePath:  statePath,
+		Options:    opts,
+	}
+}
+
+// Sync 同步文档：版本变化或输出文件不存在时重新导出，不在 docs 中的已同步文档会删除其输出文件
+func (s *Syncer) Sync(ctx context.Context, docs []SyncDocument) (*SyncResult, error) {
+	state, err := s.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(SyncResult)
+	wanted := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		wanted[doc.Token] = true
+
+		p := NewDocxMarkdownProcessor(s.LarkClient, doc.Typ, doc.Token, s.Options...)
+		if err := p.resolveDocumentId(ctx); err != nil {
+			return result, err
+		}
+		revisionId, err := s.revisionId(ctx, p.DocumentId)
+		if err != nil {
+			return result, err
+		}
+
+		entry, synced := state.Documents[doc.Token]
+		if synced && entry.RevisionId == revisionId && entry.Path == doc.Path && fileExists(doc.Path) {
+			result.Unchanged = append(result.Unchanged, doc.Token)
+			continue
+		}
+
+		md, err := p.DocxMarkdown(ctx)
+		if err != nil {
+			return result, err
+		}
+		if err := writeFile(doc.Path, []byte(md)); err != nil {
+			return result, err
+		}
+		if synced && entry.Path != doc.Path {
+			_ = os.Remove(entry.Path)
+		}
+
+		state.Documents[doc.Token] = &syncEntry{
+			Typ:        doc.Typ,
+			DocumentId: p.DocumentId,
+			RevisionId: revisionId,
+			Path:       doc.Path,
+		}
+		if err := s.saveState(state); err != nil {
+			return result, err
+		}
+		change := &SyncChange{
+			Kind:       lo.Ternary(synced, SyncUpdated, SyncAdded),
+			Token:      doc.Token,
+			DocumentId: p.DocumentId,
+			RevisionId: revisionId,
+			Paths:      []string{doc.Path},
+		}
+		if synced && entry.Path != doc.Path {
+			change.Paths = append(change.Paths, entry.Path)
+		}
+		if err := s.commit(ctx, p, change); err != nil {
+			return result, err
+		}
+		if synced {
+			result.Updated = append(result.Updated, doc.Token)
+		} else {
+			result.Added = append(result.Added, doc.Token)
+		}
+	}
+
+	tokens := lo.Keys(state.Documents)
+	sort.Strings(tokens)
+	for _, token := range tokens {
+		if wanted[token] {
+			continue
+		}
+		entry := state.Documents[token]
+		if err := os.Remove(entry.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return result, err
+		}
+		// 文档不再引用任何静态文件，之后可以通过 AssetStore.GC 清理
+		p := NewDocxMarkdownProcessor(s.LarkClient, entry.Typ, token, s.Options...)
+		if !p.StaticAsURL {
+			if err := p.assetStore().Reference(entry.DocumentId, nil); err != nil {
+				return result, err
+			}
+		}
+		delete(state.Documents, token)
+		if err := s.saveState(state); err != nil {
+			return result, err
+		}
+		change := &SyncChange{
+			Kind:       SyncDeleted,
+			Token:      token,
+			DocumentId: entry.DocumentId,
+			Paths:      []string{entry.Path},
+		}
+		if err := s.commit(ctx, p, change); err != nil {
+			return result, err
+		}
+		result.Deleted = append(result.Deleted, token)
+	}
+
+	return result, nil
+}
+
+// commit 提交文档变更，同步状态文件和本地静态文件也一并提交
+func (s *Syncer) commit(ctx context.Context, p *DocxMarkdownProcessor, change *SyncChange) error {
+	if s.Committer == nil {
+		return nil
+	}
+	change.Paths = append(change.Paths, s.StatePath)
+	if p.Assets != nil {
+		if local, ok := p.Assets.Store.(*LocalStaticStore); ok {
+			change.Paths = append(change.Paths, local.Dir)
+		}
+	}
+	return s.Committer.Commit(ctx, change)
+}
+
+// revisionId 获取文档当前的版本
+func (s *Syncer) revisionId(ctx context.Context, documentId string) (int, error) {
+	req := larkdocx.NewGetDocumentReqBuilder().DocumentId(documentId).Build()
+	resp, err := s.LarkClient.Docx.V1.Document.Get(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Success() {
+		return 0, fmt.Errorf("lark get document %s fail: code:%d, msg:%s, requestId:%s", documentId, resp.Code, resp.Msg, resp.RequestId())
+	}
+	return lo.FromPtr(resp.Data.Document.RevisionId), nil
+}
+
+func (s *Syncer) loadState() (*syncState, error) {
+	state := &syncState{}
+	data, err := os.ReadFile(s.StatePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("parse sync state %s fail: %w", s.StatePath, err)
+		}
+	}
+	if state.Documents == nil {
+		state.Documents = make(map[string]*syncEntry)
+	}
+	return state, nil
+}
+
+func (s *Syncer) saveState(state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(s.StatePath, data)
+}
+
+func writeFile(filename string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o666)
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
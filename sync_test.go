@@ -0,0 +1,83 @@
+package lark_docx_md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// addSyncDocument 添加只有标题的文档，标题为文档 id
+func addSyncDocument(server *larktest.Server, documentId string, revisionId int) {
+	server.AddDocument(documentId, larkdocx.NewBlockBuilder().BlockId(documentId).BlockType(Page).
+		Page(larkdocx.NewTextBuilder().Elements([]*larkdocx.TextElement{
+			larkdocx.NewTextElementBuilder().TextRun(larkdocx.NewTextRunBuilder().Content(documentId).Build()).Build(),
+		}).Build()).Build())
+	server.SetRevision(documentId, revisionId)
+}
+
+// exportedDocuments 返回获取了所有块的文档，即重新导出的文档
+func exportedDocuments(requests []string) []string {
+	var documents []string
+	for _, req := range requests {
+		if strings.HasPrefix(req, "GET /open-apis/docx/v1/documents/") && strings.HasSuffix(req, "/blocks") {
+			documents = append(documents, strings.TrimSuffix(strings.TrimPrefix(req, "GET /open-apis/docx/v1/documents/"), "/blocks"))
+		}
+	}
+	return documents
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	for _, documentId := range []string{"doc1", "doc2", "doc3"} {
+		addSyncDocument(server, documentId, 1)
+	}
+
+	dir := t.TempDir()
+	s := NewSyncer(server.Client(), filepath.Join(dir, "state.json"))
+	docs := []SyncDocument{
+		{Docx, "doc1", filepath.Join(dir, "doc1.md")},
+		{Docx, "doc2", filepath.Join(dir, "doc2.md")},
+		{Docx, "doc3", filepath.Join(dir, "doc3.md")},
+	}
+
+	result, err := s.Sync(context.Background(), docs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"doc1", "doc2", "doc3"}, result.Added)
+	assert.Equal(t, "added: 3, updated: 0, unchanged: 0, deleted: 0", result.String())
+	assert.Equal(t, []string{"doc1", "doc2", "doc3"}, exportedDocuments(server.Requests()))
+
+	// doc1 版本变化，doc3 不再同步
+	server.SetRevision("doc1", 2)
+	requests := len(server.Requests())
+	result, err = s.Sync(context.Background(), docs[:2])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"doc1"}, exportedDocuments(server.Requests()[requests:]))
+	assert.Equal(t, &SyncResult{
+		Updated:   []string{"doc1"},
+		Unchanged: []string{"doc2"},
+		Deleted:   []string{"doc3"},
+	}, result)
+	_, err = os.Stat(filepath.Join(dir, "doc3.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	// 输出文件被删除后重新导出
+	assert.NoError(t, os.Remove(filepath.Join(dir, "doc2.md")))
+	requests = len(server.Requests())
+	result, err = NewSyncer(server.Client(), filepath.Join(dir, "state.json")).Sync(context.Background(), docs[:2])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"doc2"}, exportedDocuments(server.Requests()[requests:]))
+	assert.Equal(t, "added: 0, updated: 1, unchanged: 1, deleted: 0", result.String())
+	data, _ := os.ReadFile(filepath.Join(dir, "doc2.md"))
+	assert.True(t, strings.HasPrefix(string(data), "# doc2\n\n***"), string(data))
+
+	// 获取文档版本失败时返回错误
+	_, err = s.Sync(context.Background(), []SyncDocument{{Docx, "doc4", filepath.Join(dir, "doc4.md")}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/samber/lo"
+)
+
+// DefaultSyncedDepth 引用同步块默认的最大嵌套层数
+const DefaultSyncedDepth = 3
+
+type syncedKey struct{}
+
+// syncedPath 正在展开的源同步块和引用同步块的嵌套层数，用于检测循环引用和限制嵌套层数
+type syncedPath struct {
+	sources []string // 源同步块的 block id
+	depth   int
+}
+
+// withSyncedSource 进入源同步块，reference 表示是否通过引用同步块进入
+func withSyncedSource(ctx context.Context, blockId string, reference bool) context.Context {
+	path := syncedPathOf(ctx)
+	path.sources = append(path.sources[:len(path.sources):len(path.sources)], blockId)
+	if reference {
+		path.depth++
+	}
+	return context.WithValue(ctx, syncedKey{}, path)
+}
+
+func syncedPathOf(ctx context.Context) syncedPath {
+	path, _ := ctx.Value(syncedKey{}).(syncedPath)
+	return path
+}
+
+// syncedChildren 获取引用同步块对应的源同步块的子块，无法获取时返回空
+// 源同步块在当前文档中时直接使用，否则读出源文档的所有块
+func (p *DocxMarkdownProcessor) syncedChildren(ctx context.Context, block *larkdocx.Block, larkBlockMap map[string]*larkdocx.Block) []*Node {
+	documentId, blockId := p.extra(block).syncedSource()
+	if blockId == "" {
+		return nil
+	}
+	path := syncedPathOf(ctx)
+	if lo.Contains(path.sources, blockId) {
+		log.Printf("synced block %s in document %s references itself", blockId, documentId)
+		return nil
+	}
+	if path.depth >= p.syncedDepth() {
+		log.Printf("synced block %s in document %s exceeds max depth %d", blockId, documentId, p.syncedDepth())
+		return nil
+	}
+
+	blockMap := larkBlockMap
+	if _, ok := blockMap[blockId]; !ok {
+		blockMap = p.documentBlocks(ctx, documentId)
+	}
+	sourceBlock := blockMap[blockId]
+	if sourceBlock == nil {
+		return nil
+	}
+
+	ctx = withSyncedSource(ctx, blockId, true)
+	var children []*Node
+	for _, c := range sourceBlock.Children {
+		children = append(children, p.listTransformToTree(ctx, blockMap[c], blockMap))
+	}
+	return children
+}
+
+// documentBlocks 读出其他文档的所有块，同一个文档只读一次，失败时返回空
+func (p *DocxMarkdownProcessor) documentBlocks(ctx context.Context, documentId string) map[string]*larkdocx.Block {
+	if p.LarkClient == nil || documentId == "" {
+		return nil
+	}
+	if blockMap, ok := p.syncedDocuments[documentId]; ok {
+		return blockMap
+	}
+	if p.syncedDocuments == nil {
+		p.syncedDocuments = make(map[string]map[string]*larkdocx.Block)
+	}
+
+	blocks, err := p.listDocumentBlocks(ctx, documentId)
+	if err != nil {
+		log.Printf("read synced source document %s fail: %s", documentId, err)
+	}
+	p.syncedDocuments[documentId] = lo.SliceToMap(blocks, func(item *larkdocx.Block) (string, *larkdocx.Block) {
+		return *item.BlockId, item
+	})
+	return p.syncedDocuments[documentId]
+}
+
+// syncedDepth 返回引用同步块的最大嵌套层数
+func (p *DocxMarkdownProcessor) syncedDepth() int {
+	if depth := p.config().SyncedDepth; depth != 0 {
+		return depth
+	}
+	return DefaultSyncedDepth
+}
+
+// BlockReferenceSyncedMarkdown 引用同步块的内容为源同步块的子块，无法获取时输出注释
+func (p *DocxMarkdownProcessor) BlockReferenceSyncedMarkdown(ctx context.Context, root *Node) string {
+	if len(root.ChildrenNode) > 0 {
+		return ""
+	}
+	documentId, _ := p.extra(root.Block).syncedSource()
+	return fmt.Sprintf("<!-- synced block from document %s can not be resolved -->", documentId)
+}
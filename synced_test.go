@@ -0,0 +1,80 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncedDocumentJSON 文档包含一个源同步块，源同步块中的引用同步块引用 refDocument 的源同步块
+func syncedDocumentJSON(documentId, refDocument string) []byte {
+	return []byte(fmt.Sprintf(`[
+		{"block_id": "%[1]s", "block_type": 1, "children": ["%[1]s_source"], "page": {"elements": [{"text_run": {"content": "%[1]s"}}]}},
+		{"block_id": "%[1]s_source", "block_type": 49, "parent_id": "%[1]s", "children": ["%[1]s_text", "%[1]s_ref"], "source_synced": {}},
+		{"block_id": "%[1]s_text", "block_type": 2, "parent_id": "%[1]s_source", "text": {"elements": [{"text_run": {"content": "%[1]s 的同步内容"}}]}},
+		{"block_id": "%[1]s_ref", "block_type": 50, "parent_id": "%[1]s_source", "reference_synced": {"source_document_id": "%[2]s", "source_block_id": "%[2]s_source"}}
+	]`, documentId, refDocument))
+}
+
+func TestDocxMarkdownProcessor_DocxMarkdownSynced(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	assert.NoError(t, server.AddDocumentJSON("doxcnA", syncedDocumentJSON("doxcnA", "doxcnB")))
+	assert.NoError(t, server.AddDocumentJSON("doxcnB", syncedDocumentJSON("doxcnB", "doxcnC")))
+	assert.NoError(t, server.AddDocumentJSON("doxcnC", syncedDocumentJSON("doxcnC", "doxcnA")))
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			"cycle",
+			nil,
+			// A -> B -> C -> A，A 的源同步块正在展开，不再展开
+			"# doxcnA\n\ndoxcnA 的同步内容\n\ndoxcnB 的同步内容\n\ndoxcnC 的同步内容\n\n<!-- synced block from document doxcnA can not be resolved -->",
+		},
+		{
+			"max depth",
+			[]Option{UseSyncedDepth(1)},
+			"# doxcnA\n\ndoxcnA 的同步内容\n\ndoxcnB 的同步内容\n\n<!-- synced block from document doxcnC can not be resolved -->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnA", tt.opts...)
+			got, err := p.DocxMarkdown(context.Background())
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(got, tt.want+"\n\n***"), got)
+		})
+	}
+
+	// 每个源文档只读一次
+	var lists int
+	for _, req := range server.Requests() {
+		if req == "GET /open-apis/docx/v1/documents/doxcnB/blocks" {
+			lists++
+		}
+	}
+	assert.Equal(t, 2, lists)
+}
+
+func TestConvertJSON_Synced(t *testing.T) {
+	// 源同步块在当前文档中时不需要客户端
+	md, err := ConvertJSON(syncedDocumentJSON("doxcnA", "doxcnA"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(md, "# doxcnA\n\ndoxcnA 的同步内容\n\n<!-- synced block from document doxcnA can not be resolved -->\n\n***"), md)
+
+	md, err = ConvertJSON([]byte(`[
+		{"block_id": "doxcnA", "block_type": 1, "children": ["source", "ref"], "page": {"elements": [{"text_run": {"content": "标题"}}]}},
+		{"block_id": "source", "block_type": 49, "parent_id": "doxcnA", "children": ["text"], "source_synced": {}},
+		{"block_id": "text", "block_type": 2, "parent_id": "source", "text": {"elements": [{"text_run": {"content": "同步内容"}}]}},
+		{"block_id": "ref", "block_type": 50, "parent_id": "doxcnA", "reference_synced": {"source_document_id": "doxcnA", "source_block_id": "source"}}
+	]`))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(md, "# 标题\n\n同步内容\n\n同步内容\n\n***"), md)
+}
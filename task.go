@@ -0,0 +1,92 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larktask "github.com/larksuite/oapi-sdk-go/v3/service/task/v2"
+	"github.com/samber/lo"
+)
+
+// taskAppLink 任务详情页的 AppLink
+const taskAppLink = "https://applink.feishu.cn/client/todo/detail?guid=%s"
+
+// BlockTaskMarkdown 任务输出为任务列表项，eg. - [ ] 任务标题 (负责人, 截止时间)
+// 无法获取任务详情时输出为任务链接
+func (p *DocxMarkdownProcessor) BlockTaskMarkdown(ctx context.Context, block *larkdocx.Block) string {
+	if block.Task == nil || lo.FromPtr(block.Task.TaskId) == "" {
+		return "<!-- empty task -->"
+	}
+	guid := *block.Task.TaskId
+	task := p.task(ctx, guid)
+	if task == nil {
+		return fmt.Sprintf("- [ ] [Task](%s)", fmt.Sprintf(taskAppLink, guid))
+	}
+
+	checkbox := lo.Ternary(lo.FromPtr(task.CompletedAt) != "" && lo.FromPtr(task.CompletedAt) != "0", "[x]", "[ ]")
+	var details []string
+	for _, member := range task.Members {
+		if lo.FromPtr(member.Role) == "assignee" {
+			details = append(details, p.memberName(ctx, member))
+		}
+	}
+	if due := taskDue(task.Due, p.reminderLocation()); due != "" {
+		details = append(details, due)
+	}
+
+	text := fmt.Sprintf("- %s %s", checkbox, escapeText(ctx, lo.FromPtr(task.Summary), false))
+	if len(details) > 0 {
+		text += fmt.Sprintf(" (%s)", escapeText(ctx, strings.Join(details, ", "), false))
+	}
+	return text
+}
+
+// task 通过任务 v2 接口获取任务详情，失败时返回空
+func (p *DocxMarkdownProcessor) task(ctx context.Context, guid string) *larktask.Task {
+	if p.LarkClient == nil || guid == "" {
+		return nil
+	}
+	req := larktask.NewGetTaskReqBuilder().TaskGuid(guid).UserIdType("open_id").Build()
+	resp, err := p.LarkClient.Task.V2.Task.Get(ctx, req)
+	if err != nil {
+		log.Printf("lark get task %s fail: %s", guid, err)
+		return nil
+	}
+	if !resp.Success() || resp.Data.Task == nil {
+		log.Printf("lark get task %s fail: code:%d, msg:%s, requestId:%s", guid, resp.Code, resp.Msg, resp.RequestId())
+		return nil
+	}
+	return resp.Data.Task
+}
+
+// memberName 返回任务成员的姓名，任务详情中没有姓名时通过通讯录获取，失败时返回成员 id
+func (p *DocxMarkdownProcessor) memberName(ctx context.Context, member *larktask.Member) string {
+	if name := lo.FromPtr(member.Name); name != "" {
+		return name
+	}
+	if user := p.users.user(ctx, p.LarkClient, lo.FromPtr(member.Id)); user != nil && lo.FromPtr(user.Name) != "" {
+		return *user.Name
+	}
+	return lo.FromPtr(member.Id)
+}
+
+// taskDue 返回任务截止时间，截止到日期时只输出日期，否则按照 loc 时区输出
+func taskDue(due *larktask.Due, loc *time.Location) string {
+	if due == nil {
+		return ""
+	}
+	ms, err := strconv.ParseInt(lo.FromPtr(due.Timestamp), 10, 64)
+	if err != nil || ms == 0 {
+		return ""
+	}
+	t := time.UnixMilli(ms)
+	if lo.FromPtr(due.IsAllDay) {
+		return t.UTC().Format("2006-01-02")
+	}
+	return t.In(loc).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,67 @@
+package lark_docx_md
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larktask "github.com/larksuite/oapi-sdk-go/v3/service/task/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocxMarkdownProcessor_BlockTaskMarkdown(t *testing.T) {
+	server := larktest.NewServer()
+	defer server.Close()
+	server.AddTask(larktask.NewTaskBuilder().
+		Guid("task-1").
+		Summary("发布 *v1.0*").
+		Members([]*larktask.Member{
+			larktask.NewMemberBuilder().Id("ou_1").Role("assignee").Name("张三").Build(),
+			larktask.NewMemberBuilder().Id("ou_2").Role("follower").Name("李四").Build(),
+			larktask.NewMemberBuilder().Id("ou_3").Role("assignee").Build(),
+			larktask.NewMemberBuilder().Id("ou_4").Role("assignee").Build(),
+		}).
+		Due(larktask.NewDueBuilder().Timestamp("1719705600000").IsAllDay(true).Build()).
+		CompletedAt("0").
+		Build())
+	server.AddTask(larktask.NewTaskBuilder().Guid("task-2").Summary("写文档").CompletedAt("1719705600000").Build())
+	server.AddTask(larktask.NewTaskBuilder().Guid("task-4").Summary("评审").
+		Due(larktask.NewDueBuilder().Timestamp("1719730800000").IsAllDay(false).Build()). // 2024-06-30 15:00:00 +08:00
+		Build())
+	// 任务详情中没有姓名的成员通过通讯录获取姓名
+	server.AddUser("ou_3", "王五")
+	shanghai := time.FixedZone("CST", 8*60*60)
+
+	task := func(guid string) *larkdocx.Block {
+		return larkdocx.NewBlockBuilder().
+			BlockType(Task).
+			Task(larkdocx.NewTaskBuilder().TaskId(guid).Build()).
+			Build()
+	}
+	tests := []struct {
+		name   string
+		opts   []Option
+		client bool
+		block  *larkdocx.Block
+		want   string
+	}{
+		{"assignee and due", nil, true, task("task-1"), `- [ ] 发布 \*v1.0\* (张三, 王五, ou_4, 2024-06-30)`},
+		{"due time", []Option{UseReminderFormat("", "", shanghai)}, true, task("task-4"), "- [ ] 评审 (2024-06-30 15:00)"},
+		{"due time utc", []Option{UseReminderFormat("", "", time.UTC)}, true, task("task-4"), "- [ ] 评审 (2024-06-30 07:00)"},
+		{"completed", nil, true, task("task-2"), "- [x] 写文档"},
+		{"not found", nil, true, task("task-3"), "- [ ] [Task](https://applink.feishu.cn/client/todo/detail?guid=task-3)"},
+		{"offline", nil, false, task("task-1"), "- [ ] [Task](https://applink.feishu.cn/client/todo/detail?guid=task-1)"},
+		{"without task", nil, true, larkdocx.NewBlockBuilder().BlockType(Task).Build(), "<!-- empty task -->"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDocxMarkdownProcessor(nil, Docx, "doxcnPage", tt.opts...)
+			if tt.client {
+				p.LarkClient = server.Client()
+			}
+			assert.Equal(t, tt.want, p.BlockTaskMarkdown(context.Background(), tt.block))
+		})
+	}
+}
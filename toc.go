@@ -0,0 +1,138 @@
+package lark_docx_md
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/samber/lo"
+)
+
+// tocHeading 目录中的标题
+type tocHeading struct {
+	level  int    // 标题级别，文档标题为 0
+	text   string // 标题的纯文本
+	anchor string // 标题锚点
+}
+
+// headings 按文档顺序返回所有有锚点的标题：输出了块锚点时使用块 id，否则使用与 GitHub 一致的锚点
+func (p *DocxMarkdownProcessor) headings(ctx context.Context, root *Node) []tocHeading {
+	var (
+		headings []tocHeading
+		slugs    = make(map[string]int)
+		walk     func(node *Node)
+	)
+	walk = func(node *Node) {
+		if node == nil || node.Block == nil {
+			return
+		}
+		var (
+			blockType = lo.FromPtr(node.BlockType)
+			level     int
+			text      string
+		)
+		switch {
+		case blockType == Page:
+			text = p.TextMarkdown(ctx, node.Page, true)
+		case blockType >= Heading1 && blockType <= Heading9:
+			level, text = blockType-2, p.TextMarkdown(ctx, headingText(node.Block), true)
+		default:
+			level = -1
+		}
+		if level >= 0 {
+			// 不输出为 Markdown 标题的块没有 GitHub 锚点，也不参与锚点去重，eg. 折叠为 <details> 的标题
+			anchor := ""
+			if p.isMarkdownHeading(blockType) && !p.isFoldedDetails(node) {
+				anchor = uniqueSlug(slugs, text)
+			}
+			if p.hasAnchor(node.Block) {
+				anchor = *node.BlockId
+			}
+			if anchor != "" {
+				headings = append(headings, tocHeading{level: level, text: text, anchor: anchor})
+			}
+		}
+		for _, child := range node.ChildrenNode {
+			walk(child)
+		}
+	}
+	walk(root)
+	return headings
+}
+
+// tocMarkdown 输出目录，只包含级别在 [TOCMinLevel, TOCMaxLevel] 中的标题
+func (p *DocxMarkdownProcessor) tocMarkdown(ctx context.Context, headings []tocHeading) string {
+	minLevel := lo.Ternary(p.TOCMinLevel > 0, p.TOCMinLevel, 1)
+	maxLevel := lo.Ternary(p.TOCMaxLevel > 0, p.TOCMaxLevel, 9)
+	headings = lo.Filter(headings, func(h tocHeading, _ int) bool {
+		return h.level >= minLevel && h.level <= maxLevel
+	})
+	if len(headings) == 0 {
+		return ""
+	}
+
+	// 按照之前级别更高的标题嵌套，跳过的级别不额外缩进
+	var (
+		parents []int
+		lines   []string
+	)
+	linkCtx := withEscape(ctx, escapeLinkText)
+	for _, h := range headings {
+		for len(parents) > 0 && parents[len(parents)-1] >= h.level {
+			parents = parents[:len(parents)-1]
+		}
+		indent := strings.Repeat("  ", len(parents))
+		parents = append(parents, h.level)
+		lines = append(lines, fmt.Sprintf("%s- [%s](#%s)", indent, escapeText(linkCtx, strings.ReplaceAll(h.text, "\n", " "), false), h.anchor))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isTOCMarker 文本块的内容是否为目录的插入位置
+func (p *DocxMarkdownProcessor) isTOCMarker(ctx context.Context, node *Node) bool {
+	return p.TOCMarker != "" && lo.FromPtr(node.BlockType) == Text &&
+		strings.TrimSpace(p.TextMarkdown(ctx, node.Text, true)) == p.TOCMarker
+}
+
+// containsTOCMarker 文档中是否有目录的插入位置
+func (p *DocxMarkdownProcessor) containsTOCMarker(ctx context.Context, node *Node) bool {
+	if node == nil || node.Block == nil {
+		return false
+	}
+	if p.isTOCMarker(ctx, node) {
+		return true
+	}
+	return lo.ContainsBy(node.ChildrenNode, func(child *Node) bool {
+		return p.containsTOCMarker(ctx, child)
+	})
+}
+
+// uniqueSlug 返回标题的 GitHub 锚点，重复的锚点依次加上 -1、-2 后缀
+func uniqueSlug(slugs map[string]int, text string) string {
+	base := slug(text)
+	s := base
+	for {
+		if _, ok := slugs[s]; !ok {
+			break
+		}
+		slugs[base]++
+		s = fmt.Sprintf("%s-%d", base, slugs[base])
+	}
+	slugs[s] = 0
+	return s
+}
+
+// slug 按照 GitHub 的规则生成锚点：转为小写，去掉标点和符号，空格转为 -，保留中日韩文字
+func slug(text string) string {
+	buf := new(strings.Builder)
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ':
+			buf.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,56 @@
+package lark_docx_md
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlug(t *testing.T) {
+	slugs := make(map[string]int)
+	for _, tt := range []struct {
+		text string
+		want string
+	}{
+		{"Getting Started!", "getting-started"},
+		{"API 设计 (v2)", "api-设计-v2"},
+		{"快速开始：安装", "快速开始安装"},
+		{"snake_case & kebab-case", "snake_case--kebab-case"},
+		{"Getting Started", "getting-started-1"},
+		{"Getting Started", "getting-started-2"},
+		{"Getting Started 1", "getting-started-1-1"},
+		{"🎉 发布", "-发布"},
+	} {
+		assert.Equal(t, tt.want, uniqueSlug(slugs, tt.text), tt.text)
+	}
+}
+
+func TestConvertJSON_TOC(t *testing.T) {
+	data := []byte(`[
+		{"block_id": "doxcnTOC", "block_type": 1, "children": ["intro", "h1", "h2", "h4", "h5"], "page": {"elements": [{"text_run": {"content": "概述"}}]}},
+		{"block_id": "intro", "block_type": 2, "parent_id": "doxcnTOC", "text": {"elements": [{"text_run": {"content": "[TOC]"}}]}},
+		{"block_id": "h1", "block_type": 3, "parent_id": "doxcnTOC", "heading1": {"elements": [{"text_run": {"content": "概述"}}]}},
+		{"block_id": "h2", "block_type": 4, "parent_id": "doxcnTOC", "children": ["h3"], "heading2": {"elements": [{"text_run": {"content": "API "}}, {"text_run": {"content": "设计", "text_element_style": {"bold": true}}}, {"text_run": {"content": " (v2)"}}]}},
+		{"block_id": "h3", "block_type": 5, "parent_id": "h2", "heading3": {"elements": [{"text_run": {"content": "*细节*"}}]}},
+		{"block_id": "h4", "block_type": 6, "parent_id": "doxcnTOC", "heading4": {"elements": [{"text_run": {"content": "不在目录中"}}]}},
+		{"block_id": "h5", "block_type": 3, "parent_id": "doxcnTOC", "heading1": {"elements": [{"text_run": {"content": "概述"}}]}}
+	]`)
+	toc := "- [概述](#概述-1)\n" +
+		"  - [API 设计 (v2)](#api-设计-v2)\n" +
+		"    - [\\*细节\\*](#细节)\n" +
+		"- [概述](#概述-2)"
+
+	got, err := ConvertJSON(data, UseTOC(0, 3, "[TOC]"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "# 概述\n\n"+toc+"\n\n# 概述"), got)
+
+	// 找不到插入位置时插入在文档标题之后
+	got, err = ConvertJSON(data, UseTOC(2, 0, "<!-- toc -->"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "# 概述\n\n- [API 设计 (v2)](#api-设计-v2)\n  - [\\*细节\\*](#细节)\n    - [不在目录中](#不在目录中)\n\n\\[TOC\\]"), got)
+
+	got, err = ConvertJSON(data)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "# 概述\n\n\\[TOC\\]\n\n# 概述"), got)
+}
@@ -0,0 +1,42 @@
+package lark_docx_md
+
+import (
+	"context"
+	"log"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+// userCache 通过通讯录接口获取用户信息并缓存，评论中的用户名和 git 提交作者共用
+type userCache struct {
+	users map[string]*larkcontact.User // user open id -> 用户，获取失败时为空
+}
+
+// user 获取用户信息，失败时返回空，失败的结果同样会被缓存
+func (c *userCache) user(ctx context.Context, client *lark.Client, openId string) *larkcontact.User {
+	if openId == "" || client == nil {
+		return nil
+	}
+	if user, ok := c.users[openId]; ok {
+		return user
+	}
+	if c.users == nil {
+		c.users = make(map[string]*larkcontact.User)
+	}
+
+	req := larkcontact.NewGetUserReqBuilder().UserId(openId).UserIdType("open_id").Build()
+	resp, err := client.Contact.V3.User.Get(ctx, req)
+	if err != nil {
+		log.Printf("lark get user %s fail: %s", openId, err)
+		c.users[openId] = nil
+		return nil
+	}
+	if !resp.Success() || resp.Data.User == nil {
+		log.Printf("lark get user %s fail: code:%d, msg:%s, requestId:%s", openId, resp.Code, resp.Msg, resp.RequestId())
+		c.users[openId] = nil
+		return nil
+	}
+	c.users[openId] = resp.Data.User
+	return resp.Data.User
+}
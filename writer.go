@@ -0,0 +1,52 @@
+package lark_docx_md
+
+import (
+	"io"
+	"strings"
+)
+
+// tightMarker 段落之间只换一行的标记，见 FixTexts
+const tightMarker = "0x3f3f3f\n\n"
+
+// markdownWriter 逐段写入块的文本，段落之间空一行，并去掉 tightMarker
+type markdownWriter struct {
+	w       io.Writer
+	err     error
+	n       int    // 已写入的段落数量
+	pending string // 可能是 tightMarker 开头的部分，等下一段写入时再处理
+}
+
+// write 写入段落，写入失败后不再写入，错误记录在 err 中
+func (mw *markdownWriter) write(texts ...string) {
+	for _, text := range texts {
+		if mw.n > 0 {
+			text = "\n\n" + text
+		}
+		mw.n++
+		mw.flush(mw.pending+text, false)
+	}
+}
+
+// close 写入剩余的内容
+func (mw *markdownWriter) close() error {
+	mw.flush(mw.pending, true)
+	return mw.err
+}
+
+func (mw *markdownWriter) flush(s string, final bool) {
+	if mw.err != nil {
+		return
+	}
+	s = strings.ReplaceAll(s, tightMarker, "")
+	keep := 0
+	if !final {
+		for i := len(tightMarker) - 1; i > 0; i-- {
+			if strings.HasSuffix(s, tightMarker[:i]) {
+				keep = i
+				break
+			}
+		}
+	}
+	mw.pending = s[len(s)-keep:]
+	_, mw.err = io.WriteString(mw.w, s[:len(s)-keep])
+}
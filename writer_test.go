@@ -0,0 +1,65 @@
+package lark_docx_md
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/A11Might/lark_docx_md/larktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownWriter(t *testing.T) {
+	buf := new(strings.Builder)
+	mw := &markdownWriter{w: buf}
+	// tightMarker 可能被拆在两段之间
+	mw.write("- 一\n0x3f3f3f", "- 二", "```\n0x3f3f3f\n")
+	assert.Equal(t, "- 一\n- 二\n\n```\n", buf.String())
+	mw.write("---")
+	assert.NoError(t, mw.close())
+	assert.Equal(t, "- 一\n- 二\n\n```\n\n---", buf.String())
+}
+
+// chunkWriter 记录每次写入的内容，写入 limit 次后返回错误
+type chunkWriter struct {
+	chunks []string
+	limit  int
+}
+
+func (w *chunkWriter) Write(b []byte) (int, error) {
+	if w.limit > 0 && len(w.chunks) >= w.limit {
+		return 0, errors.New("disk full")
+	}
+	w.chunks = append(w.chunks, string(b))
+	return len(b), nil
+}
+
+func TestDocxMarkdownProcessor_WriteMarkdown(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", "basic.json"))
+	assert.NoError(t, err)
+	want, err := os.ReadFile(filepath.Join("testdata", "golden", "basic.md"))
+	assert.NoError(t, err)
+
+	server := larktest.NewServer()
+	defer server.Close()
+	assert.NoError(t, server.AddDocumentJSON("doxcnBasic", data))
+
+	// 顶层块逐个写入
+	w := &chunkWriter{}
+	p := NewDocxMarkdownProcessor(server.Client(), Docx, "doxcnBasic")
+	assert.NoError(t, p.WriteMarkdown(context.Background(), w))
+	assert.Equal(t, string(want), strings.Join(w.chunks, ""))
+	assert.Greater(t, len(w.chunks), 10)
+
+	got, err := p.DocxMarkdown(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), got)
+
+	// 写入失败后停止写入并返回错误
+	w = &chunkWriter{limit: 3}
+	assert.EqualError(t, p.WriteMarkdown(context.Background(), w), "disk full")
+	assert.Len(t, w.chunks, 3)
+}